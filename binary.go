@@ -0,0 +1,118 @@
+package vaxdata
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+)
+
+// The VAX floating point types marshal to the bytes of the value as stored
+// on disk, the form taken by Float32fromVaxFFloat and the other conversion
+// functions, rather than as the integers underlying the types. The bits are
+// carried as is, so reserved operands and dirty zeros round trip unchanged.
+
+var (
+	_ encoding.BinaryMarshaler   = VaxFFloat(0)
+	_ encoding.BinaryAppender    = VaxFFloat(0)
+	_ encoding.BinaryUnmarshaler = (*VaxFFloat)(nil)
+)
+
+// AppendBinary appends the F_Float to b as stored on disk.
+func (v VaxFFloat) AppendBinary(b []byte) ([]byte, error) {
+	return binary.BigEndian.AppendUint32(b, uint32(v)), nil
+}
+
+// MarshalBinary returns the F_Float as stored on disk.
+func (v VaxFFloat) MarshalBinary() ([]byte, error) {
+	return v.AppendBinary(make([]byte, 0, 4))
+}
+
+// UnmarshalBinary sets v to the F_Float stored on disk as b.
+func (v *VaxFFloat) UnmarshalBinary(b []byte) error {
+	if len(b) != 4 {
+		return fmt.Errorf("VAX F_Float is 4 bytes, not %d", len(b))
+	}
+	*v = VaxFFloat(binary.BigEndian.Uint32(b))
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder as MarshalBinary.
+func (v VaxFFloat) GobEncode() ([]byte, error) { return v.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder as UnmarshalBinary.
+func (v *VaxFFloat) GobDecode(b []byte) error { return v.UnmarshalBinary(b) }
+
+// AppendBinary appends the D_Float to b as stored on disk.
+func (v VaxDFloat) AppendBinary(b []byte) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(b, uint64(v)), nil
+}
+
+// MarshalBinary returns the D_Float as stored on disk.
+func (v VaxDFloat) MarshalBinary() ([]byte, error) {
+	return v.AppendBinary(make([]byte, 0, 8))
+}
+
+// UnmarshalBinary sets v to the D_Float stored on disk as b.
+func (v *VaxDFloat) UnmarshalBinary(b []byte) error {
+	if len(b) != 8 {
+		return fmt.Errorf("VAX D_Float is 8 bytes, not %d", len(b))
+	}
+	*v = VaxDFloat(binary.BigEndian.Uint64(b))
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder as MarshalBinary.
+func (v VaxDFloat) GobEncode() ([]byte, error) { return v.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder as UnmarshalBinary.
+func (v *VaxDFloat) GobDecode(b []byte) error { return v.UnmarshalBinary(b) }
+
+// AppendBinary appends the G_Float to b as stored on disk.
+func (v VaxGFloat) AppendBinary(b []byte) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(b, uint64(v)), nil
+}
+
+// MarshalBinary returns the G_Float as stored on disk.
+func (v VaxGFloat) MarshalBinary() ([]byte, error) {
+	return v.AppendBinary(make([]byte, 0, 8))
+}
+
+// UnmarshalBinary sets v to the G_Float stored on disk as b.
+func (v *VaxGFloat) UnmarshalBinary(b []byte) error {
+	if len(b) != 8 {
+		return fmt.Errorf("VAX G_Float is 8 bytes, not %d", len(b))
+	}
+	*v = VaxGFloat(binary.BigEndian.Uint64(b))
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder as MarshalBinary.
+func (v VaxGFloat) GobEncode() ([]byte, error) { return v.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder as UnmarshalBinary.
+func (v *VaxGFloat) GobDecode(b []byte) error { return v.UnmarshalBinary(b) }
+
+// AppendBinary appends the H_Float to b as stored on disk.
+func (v VaxHFloat) AppendBinary(b []byte) ([]byte, error) {
+	return append(b, v[:]...), nil
+}
+
+// MarshalBinary returns the H_Float as stored on disk.
+func (v VaxHFloat) MarshalBinary() ([]byte, error) {
+	return v.AppendBinary(make([]byte, 0, 16))
+}
+
+// UnmarshalBinary sets v to the H_Float stored on disk as b.
+func (v *VaxHFloat) UnmarshalBinary(b []byte) error {
+	if len(b) != 16 {
+		return fmt.Errorf("VAX H_Float is 16 bytes, not %d", len(b))
+	}
+	copy(v[:], b)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder as MarshalBinary.
+func (v VaxHFloat) GobEncode() ([]byte, error) { return v.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder as UnmarshalBinary.
+func (v *VaxHFloat) GobDecode(b []byte) error { return v.UnmarshalBinary(b) }
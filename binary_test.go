@@ -0,0 +1,89 @@
+package vaxdata
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestVaxFloatBinary(t *testing.T) {
+	pi := []byte{0x0F, 0xD0, 0x41, 0x49}
+	f := VaxFFloat(0x0FD04149)
+	b, err := f.MarshalBinary()
+	if err != nil || !bytes.Equal(b, pi) {
+		t.Errorf("VaxFFloat(%08X).MarshalBinary() == %X, %v, want %X", uint32(f), b, err, pi)
+	}
+	if x, err := Float32fromVaxFFloat(b); err != nil || x != 3.141590 {
+		t.Errorf("Float32fromVaxFFloat(%X) == %v, %v, want %v", b, x, err, 3.141590)
+	}
+
+	var tests = []struct {
+		v    interface{ AppendBinary([]byte) ([]byte, error) }
+		u    interface{ UnmarshalBinary([]byte) error }
+		size int
+	}{
+		{VaxFFloat(0x00000001), new(VaxFFloat), 4}, // dirty zero
+		{VaxFFloat(0x00008000), new(VaxFFloat), 4}, // reserved operand
+		{VaxDFloat(0x68C0A2210FDA4149), new(VaxDFloat), 8},
+		{VaxGFloat(0x2D18544421FB4029), new(VaxGFloat), 8},
+		{VaxGFloat(0x0000000000000001), new(VaxGFloat), 8},
+		{VaxHFloat{0: 1, 15: 0x40}, new(VaxHFloat), 16},
+	}
+
+	for _, tt := range tests {
+		b, err := tt.v.AppendBinary([]byte{0xAA})
+		if err != nil || len(b) != 1+tt.size || b[0] != 0xAA {
+			t.Errorf("%T(%v).AppendBinary == %X, %v", tt.v, tt.v, b, err)
+			continue
+		}
+		if err := tt.u.UnmarshalBinary(b[1:]); err != nil {
+			t.Errorf("%T.UnmarshalBinary(%X) raised unexpected error: %q", tt.u, b[1:], err)
+		}
+		if got := reflectValue(tt.u); got != tt.v {
+			t.Errorf("%T.UnmarshalBinary(%X) == %v, want %v", tt.u, b[1:], got, tt.v)
+		}
+		if err := tt.u.UnmarshalBinary(b); err == nil {
+			t.Errorf("%T.UnmarshalBinary(%X) did not raise an error", tt.u, b)
+		}
+	}
+}
+
+// reflectValue returns the value pointed to by one of the VAX float types.
+func reflectValue(p any) any {
+	switch p := p.(type) {
+	case *VaxFFloat:
+		return *p
+	case *VaxDFloat:
+		return *p
+	case *VaxGFloat:
+		return *p
+	case *VaxHFloat:
+		return *p
+	}
+	return nil
+}
+
+func TestVaxFloatGob(t *testing.T) {
+	type sample struct {
+		F VaxFFloat
+		G VaxGFloat
+		H VaxHFloat
+	}
+	in := sample{F: 0x00000001, G: 0x2D18544421FB4029, H: VaxHFloat{12: 0x01, 13: 0x40}}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("gob Encode raised unexpected error: %q", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte{0x2D, 0x18, 0x54, 0x44, 0x21, 0xFB, 0x40, 0x29}) {
+		t.Errorf("gob stream %X does not hold the G_Float as stored on disk", buf.Bytes())
+	}
+
+	var out sample
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("gob Decode raised unexpected error: %q", err)
+	}
+	if out != in {
+		t.Errorf("gob round trip == %+v, want %+v", out, in)
+	}
+}
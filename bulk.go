@@ -0,0 +1,185 @@
+package vaxdata
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// The bulk conversions below convert min(len(dst), len(src)) values and
+// return the number converted. Like libvaxdata every value is converted,
+// with faults fixed up as by the single value functions, and the first fault
+// is returned as a *ValueError. They do not allocate unless a fault is
+// raised. H_Float's are not covered, as each *big.Float is an allocation.
+
+// ValueError reports the first value of a bulk conversion to raise an error.
+type ValueError struct {
+	Index int // index of the value within the slice
+	Err   error
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("element %d: %v", e.Index, e.Err)
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeFFloats converts the F_Float's in src to float32's in dst.
+func DecodeFFloats(dst []float32, src []byte) (n int, err error) {
+	n = min(len(dst), len(src)/4)
+	for i := range dst[:n] {
+		v, e := Float32fromVaxFFloat(src[4*i : 4*i+4])
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		dst[i] = v
+	}
+	return n, err
+}
+
+// EncodeFFloats converts the float32's in src to F_Float's in dst.
+func EncodeFFloats(dst []byte, src []float32) (n int, err error) {
+	n = min(len(dst)/4, len(src))
+	for i, f := range src[:n] {
+		v, e := VaxFFloatfromFloat32(f)
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		binary.BigEndian.PutUint32(dst[4*i:], uint32(v))
+	}
+	return n, err
+}
+
+// DecodeDFloats converts the D_Float's in src to float64's in dst.
+func DecodeDFloats(dst []float64, src []byte) (n int, err error) {
+	n = min(len(dst), len(src)/8)
+	for i := range dst[:n] {
+		v, e := Float64fromVaxDFloat(src[8*i : 8*i+8])
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		dst[i] = v
+	}
+	return n, err
+}
+
+// EncodeDFloats converts the float64's in src to D_Float's in dst.
+func EncodeDFloats(dst []byte, src []float64) (n int, err error) {
+	n = min(len(dst)/8, len(src))
+	for i, f := range src[:n] {
+		v, e := VaxDFloatfromFloat64(f)
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		binary.BigEndian.PutUint64(dst[8*i:], uint64(v))
+	}
+	return n, err
+}
+
+// DecodeGFloats converts the G_Float's in src to float64's in dst.
+func DecodeGFloats(dst []float64, src []byte) (n int, err error) {
+	n = min(len(dst), len(src)/8)
+	for i := range dst[:n] {
+		v, e := Float64fromVaxGFloat(src[8*i : 8*i+8])
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		dst[i] = v
+	}
+	return n, err
+}
+
+// EncodeGFloats converts the float64's in src to G_Float's in dst.
+func EncodeGFloats(dst []byte, src []float64) (n int, err error) {
+	n = min(len(dst)/8, len(src))
+	for i, f := range src[:n] {
+		v, e := VaxGFloatfromFloat64(f)
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		binary.BigEndian.PutUint64(dst[8*i:], uint64(v))
+	}
+	return n, err
+}
+
+// DecodeFComplexes converts the F_Complex's in src to complex64's in dst.
+func DecodeFComplexes(dst []complex64, src []byte) (n int, err error) {
+	n = min(len(dst), len(src)/8)
+	for i := range dst[:n] {
+		v, e := Complex64fromVaxFComplex(src[8*i : 8*i+8])
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		dst[i] = v
+	}
+	return n, err
+}
+
+// EncodeFComplexes converts the complex64's in src to F_Complex's in dst.
+func EncodeFComplexes(dst []byte, src []complex64) (n int, err error) {
+	n = min(len(dst)/8, len(src))
+	for i, c := range src[:n] {
+		v, e := VaxFComplexfromComplex64(c)
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		binary.BigEndian.PutUint32(dst[8*i:], uint32(v.Real))
+		binary.BigEndian.PutUint32(dst[8*i+4:], uint32(v.Imag))
+	}
+	return n, err
+}
+
+// DecodeDComplexes converts the D_Complex's in src to complex128's in dst.
+func DecodeDComplexes(dst []complex128, src []byte) (n int, err error) {
+	n = min(len(dst), len(src)/16)
+	for i := range dst[:n] {
+		v, e := Complex128fromVaxDComplex(src[16*i : 16*i+16])
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		dst[i] = v
+	}
+	return n, err
+}
+
+// EncodeDComplexes converts the complex128's in src to D_Complex's in dst.
+func EncodeDComplexes(dst []byte, src []complex128) (n int, err error) {
+	n = min(len(dst)/16, len(src))
+	for i, c := range src[:n] {
+		v, e := VaxDComplexfromComplex128(c)
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		binary.BigEndian.PutUint64(dst[16*i:], uint64(v.Real))
+		binary.BigEndian.PutUint64(dst[16*i+8:], uint64(v.Imag))
+	}
+	return n, err
+}
+
+// DecodeGComplexes converts the G_Complex's in src to complex128's in dst.
+func DecodeGComplexes(dst []complex128, src []byte) (n int, err error) {
+	n = min(len(dst), len(src)/16)
+	for i := range dst[:n] {
+		v, e := Complex128fromVaxGComplex(src[16*i : 16*i+16])
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		dst[i] = v
+	}
+	return n, err
+}
+
+// EncodeGComplexes converts the complex128's in src to G_Complex's in dst.
+func EncodeGComplexes(dst []byte, src []complex128) (n int, err error) {
+	n = min(len(dst)/16, len(src))
+	for i, c := range src[:n] {
+		v, e := VaxGComplexfromComplex128(c)
+		if e != nil && err == nil {
+			err = &ValueError{i, e}
+		}
+		binary.BigEndian.PutUint64(dst[16*i:], uint64(v.Real))
+		binary.BigEndian.PutUint64(dst[16*i+8:], uint64(v.Imag))
+	}
+	return n, err
+}
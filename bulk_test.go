@@ -0,0 +1,114 @@
+package vaxdata
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestBulkConversions(t *testing.T) {
+	f := []float32{1, -3.5, 3.14159, 9.9999999e36}
+	fbuf := make([]byte, 4*len(f))
+	if n, err := EncodeFFloats(fbuf, f); n != len(f) || err != nil {
+		t.Errorf("EncodeFFloats(%v) == %d, %v", f, n, err)
+	}
+	fout := make([]float32, len(f))
+	if n, err := DecodeFFloats(fout, fbuf); n != len(f) || err != nil || !sliceEquals(fout, f) {
+		t.Errorf("DecodeFFloats(%X) == %v, %d, %v, want %v", fbuf, fout, n, err, f)
+	}
+
+	d := []float64{1, -3.5, math.Pi, 1e37}
+	dbuf := make([]byte, 8*len(d))
+	dout := make([]float64, len(d))
+	for _, c := range []struct {
+		name   string
+		encode func([]byte, []float64) (int, error)
+		decode func([]float64, []byte) (int, error)
+	}{
+		{"DFloats", EncodeDFloats, DecodeDFloats},
+		{"GFloats", EncodeGFloats, DecodeGFloats},
+	} {
+		if n, err := c.encode(dbuf, d); n != len(d) || err != nil {
+			t.Errorf("Encode%s(%v) == %d, %v", c.name, d, n, err)
+		}
+		if n, err := c.decode(dout, dbuf); n != len(d) || err != nil || !slice64Equals(dout, d) {
+			t.Errorf("Decode%s(%X) == %v, %d, %v, want %v", c.name, dbuf, dout, n, err, d)
+		}
+	}
+
+	// Conversions stop at the shorter of the two slices
+	if n, _ := DecodeFFloats(fout, fbuf[:7]); n != 1 {
+		t.Errorf("DecodeFFloats with a 7 byte source converted %d values, want 1", n)
+	}
+	if n, _ := EncodeGFloats(dbuf, d[:2]); n != 2 {
+		t.Errorf("EncodeGFloats with 2 values converted %d values, want 2", n)
+	}
+
+	c := []complex128{complex(1, -1), complex(math.Pi, 3.5)}
+	cbuf := make([]byte, 16*len(c))
+	cout := make([]complex128, len(c))
+	if n, err := EncodeGComplexes(cbuf, c); n != len(c) || err != nil {
+		t.Errorf("EncodeGComplexes(%v) == %d, %v", c, n, err)
+	}
+	if n, err := DecodeGComplexes(cout, cbuf); n != len(c) || err != nil || cout[0] != c[0] || cout[1] != c[1] {
+		t.Errorf("DecodeGComplexes(%X) == %v, %d, %v, want %v", cbuf, cout, n, err, c)
+	}
+}
+
+func TestBulkConversionFaults(t *testing.T) {
+	f := []float32{1, 2, float32(math.Inf(-1)), 3, float32(math.NaN())}
+	fbuf := make([]byte, 4*len(f))
+	n, err := EncodeFFloats(fbuf, f)
+	var verr *ValueError
+	if n != len(f) || !errors.As(err, &verr) || verr.Index != 2 {
+		t.Fatalf("EncodeFFloats(%v) == %d, %v, want a *ValueError at index 2", f, n, err)
+	}
+
+	// Mark the third value as a reserved operand
+	copy(fbuf[8:12], []byte{0x00, 0x00, 0x80, 0x00})
+	fout := make([]float32, len(f))
+	n, err = DecodeFFloats(fout, fbuf)
+	if n != len(f) || !errors.As(err, &verr) || verr.Index != 2 {
+		t.Errorf("DecodeFFloats(%X) == %d, %v, want a *ValueError at index 2", fbuf, n, err)
+	}
+	if fout[3] != 3 {
+		t.Errorf("DecodeFFloats(%X) stopped at the first fault: %v", fbuf, fout)
+	}
+}
+
+func TestBulkConversionAllocs(t *testing.T) {
+	f := make([]float32, 1024)
+	g := make([]float64, 1024)
+	for i := range f {
+		f[i] = float32(i) * 1.5
+		g[i] = float64(i) * math.Pi
+	}
+	fbuf := make([]byte, 4*len(f))
+	gbuf := make([]byte, 8*len(g))
+
+	allocs := testing.AllocsPerRun(10, func() {
+		EncodeFFloats(fbuf, f)
+		DecodeFFloats(f, fbuf)
+		EncodeGFloats(gbuf, g)
+		DecodeGFloats(g, gbuf)
+		EncodeDFloats(gbuf, g)
+		DecodeDFloats(g, gbuf)
+	})
+	if allocs != 0 {
+		t.Errorf("bulk conversions allocated %v times, want 0", allocs)
+	}
+}
+
+func BenchmarkDecodeFFloats(b *testing.B) {
+	f := make([]float32, 4096)
+	buf := make([]byte, 4*len(f))
+	for i := range f {
+		f[i] = float32(i) * 1.5
+	}
+	EncodeFFloats(buf, f)
+
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		DecodeFFloats(f, buf)
+	}
+}
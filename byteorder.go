@@ -0,0 +1,122 @@
+package vaxdata
+
+import "encoding/binary"
+
+// ByteOrder reads and writes VAX data in the manner of binary.LittleEndian,
+// adding methods for floating point values. Integers are LittleEndian, as on
+// the VAX. float32's are F_Float's, while float64's are D_Float's or
+// G_Float's depending on the order, matching the VAX FORTRAN /G_FLOATING
+// qualifier. Code written against a ByteOrder can switch between the two by
+// swapping one value. The zero ByteOrder is F.
+type ByteOrder struct {
+	gfloats bool
+}
+
+var (
+	// F is the VAX FORTRAN default data order, /NOG_FLOATING, which uses
+	// F_Float's for REAL*4 and D_Float's for REAL*8.
+	F = ByteOrder{gfloats: false}
+
+	// G is the /G_FLOATING data order, which uses F_Float's for REAL*4 and
+	// G_Float's for REAL*8.
+	G = ByteOrder{gfloats: true}
+)
+
+var (
+	_ binary.ByteOrder       = F
+	_ binary.AppendByteOrder = F
+)
+
+// Uint16 returns the WORD at the start of b.
+func (ByteOrder) Uint16(b []byte) uint16 { return binary.LittleEndian.Uint16(b) }
+
+// Uint32 returns the LONGWORD at the start of b.
+func (ByteOrder) Uint32(b []byte) uint32 { return binary.LittleEndian.Uint32(b) }
+
+// Uint64 returns the QUADWORD at the start of b.
+func (ByteOrder) Uint64(b []byte) uint64 { return binary.LittleEndian.Uint64(b) }
+
+// PutUint16 stores v as a WORD at the start of b.
+func (ByteOrder) PutUint16(b []byte, v uint16) { binary.LittleEndian.PutUint16(b, v) }
+
+// PutUint32 stores v as a LONGWORD at the start of b.
+func (ByteOrder) PutUint32(b []byte, v uint32) { binary.LittleEndian.PutUint32(b, v) }
+
+// PutUint64 stores v as a QUADWORD at the start of b.
+func (ByteOrder) PutUint64(b []byte, v uint64) { binary.LittleEndian.PutUint64(b, v) }
+
+// AppendUint16 appends v to b as a WORD.
+func (ByteOrder) AppendUint16(b []byte, v uint16) []byte {
+	return binary.LittleEndian.AppendUint16(b, v)
+}
+
+// AppendUint32 appends v to b as a LONGWORD.
+func (ByteOrder) AppendUint32(b []byte, v uint32) []byte {
+	return binary.LittleEndian.AppendUint32(b, v)
+}
+
+// AppendUint64 appends v to b as a QUADWORD.
+func (ByteOrder) AppendUint64(b []byte, v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(b, v)
+}
+
+// Float32 returns the F_Float at the start of b as a float32.
+func (ByteOrder) Float32(b []byte) (float32, error) {
+	return Float32fromVaxFFloat(b[:4])
+}
+
+// PutFloat32 stores f as an F_Float at the start of b. On error the fixup
+// value is still stored.
+func (ByteOrder) PutFloat32(b []byte, f float32) error {
+	v, err := VaxFFloatfromFloat32(f)
+	binary.BigEndian.PutUint32(b, uint32(v))
+	return err
+}
+
+// AppendFloat32 appends f to b as an F_Float. On error the fixup value is
+// still appended.
+func (ByteOrder) AppendFloat32(b []byte, f float32) ([]byte, error) {
+	v, err := VaxFFloatfromFloat32(f)
+	return binary.BigEndian.AppendUint32(b, uint32(v)), err
+}
+
+// Float64 returns the D_Float or G_Float at the start of b as a float64.
+func (o ByteOrder) Float64(b []byte) (float64, error) {
+	if o.gfloats {
+		return Float64fromVaxGFloat(b[:8])
+	}
+	return Float64fromVaxDFloat(b[:8])
+}
+
+// PutFloat64 stores f as a D_Float or G_Float at the start of b. On error
+// the fixup value is still stored.
+func (o ByteOrder) PutFloat64(b []byte, f float64) error {
+	v, err := o.vaxFloat64(f)
+	binary.BigEndian.PutUint64(b, v)
+	return err
+}
+
+// AppendFloat64 appends f to b as a D_Float or G_Float. On error the fixup
+// value is still appended.
+func (o ByteOrder) AppendFloat64(b []byte, f float64) ([]byte, error) {
+	v, err := o.vaxFloat64(f)
+	return binary.BigEndian.AppendUint64(b, v), err
+}
+
+// vaxFloat64 returns the bits of the D_Float or G_Float representation of f.
+func (o ByteOrder) vaxFloat64(f float64) (uint64, error) {
+	if o.gfloats {
+		v, err := VaxGFloatfromFloat64(f)
+		return uint64(v), err
+	}
+	v, err := VaxDFloatfromFloat64(f)
+	return uint64(v), err
+}
+
+// String returns the name of the order.
+func (o ByteOrder) String() string {
+	if o.gfloats {
+		return "G"
+	}
+	return "F"
+}
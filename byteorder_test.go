@@ -0,0 +1,52 @@
+package vaxdata
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestByteOrder(t *testing.T) {
+	cases := []struct {
+		order ByteOrder
+		f64   []byte
+	}{
+		{F, []byte{0x68, 0xC0, 0xA2, 0x21, 0x0F, 0xDA, 0x41, 0x49}},
+		{G, []byte{0x2D, 0x18, 0x54, 0x44, 0x21, 0xFB, 0x40, 0x29}},
+	}
+	f32 := []byte{0x0F, 0xD0, 0x41, 0x49}
+	for _, c := range cases {
+		var order binary.ByteOrder = c.order
+		if got := order.Uint32([]byte{0x78, 0x56, 0x34, 0x12}); got != 0x12345678 {
+			t.Errorf("%v.Uint32 == %08X, want 12345678", order, got)
+		}
+
+		b, err := c.order.AppendFloat32(nil, 3.14159)
+		if err != nil || !sliceByteEquals(b, f32) {
+			t.Errorf("%v.AppendFloat32(3.14159) == %X, %v, want %X", c.order, b, err, f32)
+		}
+		if f, err := c.order.Float32(b); err != nil || f != 3.14159 {
+			t.Errorf("%v.Float32(%X) == %v, %v, want 3.14159", c.order, b, f, err)
+		}
+
+		b, err = c.order.AppendFloat64(nil, math.Pi)
+		if err != nil || !sliceByteEquals(b, c.f64) {
+			t.Errorf("%v.AppendFloat64(Pi) == %X, %v, want %X", c.order, b, err, c.f64)
+		}
+		if f, err := c.order.Float64(b); err != nil || f != math.Pi {
+			t.Errorf("%v.Float64(%X) == %v, %v, want Pi", c.order, b, f, err)
+		}
+
+		// Fixup values are stored alongside the error
+		b = make([]byte, 8)
+		if err := c.order.PutFloat64(b, math.Inf(-1)); err == nil {
+			t.Errorf("%v.PutFloat64(-Inf) did not raise an error", c.order)
+		} else if b[6]&0x80 == 0 {
+			t.Errorf("%v.PutFloat64(-Inf) == %X, want a negative fixup", c.order, b)
+		}
+	}
+
+	if F.String() != "F" || G.String() != "G" {
+		t.Errorf("ByteOrder.String() == %q, %q", F, G)
+	}
+}
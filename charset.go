@@ -0,0 +1,209 @@
+package vaxdata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Charset converts between text and the bytes of a fixed-length VAX
+// CHARACTER field.
+type Charset interface {
+	// Decode returns the text held in b.
+	Decode(b []byte) (string, error)
+
+	// Encode returns the bytes representing s.
+	Encode(s string) ([]byte, error)
+}
+
+// byteCharset is a single byte Charset defined by a table of runes, in
+// which unassigned bytes are -1.
+type byteCharset struct {
+	name  string
+	table [256]rune
+	bytes map[rune]byte
+}
+
+func newByteCharset(name string, table [256]rune) *byteCharset {
+	cs := &byteCharset{name: name, table: table, bytes: make(map[rune]byte)}
+	for b, r := range table {
+		if r >= 0 {
+			cs.bytes[r] = byte(b)
+		}
+	}
+	return cs
+}
+
+func (cs *byteCharset) Decode(b []byte) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(b))
+	for i, c := range b {
+		r := cs.table[c]
+		if r < 0 {
+			return "", fmt.Errorf("byte 0x%02X at offset %d is not assigned in %s", c, i, cs.name)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String(), nil
+}
+
+func (cs *byteCharset) Encode(s string) ([]byte, error) {
+	b := make([]byte, 0, len(s))
+	for i, r := range s {
+		c, ok := cs.bytes[r]
+		if !ok || r == utf8.RuneError {
+			return nil, fmt.Errorf("%q at offset %d has no %s representation", r, i, cs.name)
+		}
+		b = append(b, c)
+	}
+	return b, nil
+}
+
+func (cs *byteCharset) String() string {
+	return cs.name
+}
+
+var (
+	// ASCII is the 7-bit US-ASCII character set.
+	ASCII Charset
+
+	// Latin1 is the ISO 8859-1 character set.
+	Latin1 Charset
+
+	// DECMCS is the DEC Multinational Character Set used by VMS, which
+	// differs from ISO 8859-1 in a handful of positions and leaves others
+	// unassigned.
+	DECMCS Charset
+)
+
+func init() {
+	var ascii, latin1, mcs [256]rune
+	for i := range ascii {
+		ascii[i], latin1[i], mcs[i] = -1, rune(i), rune(i)
+		if i < 0x80 {
+			ascii[i] = rune(i)
+		}
+	}
+
+	// DEC MCS departs from ISO 8859-1 in the upper half
+	for _, b := range []byte{0xA0, 0xA4, 0xA6, 0xAC, 0xAD, 0xAE, 0xAF, 0xB4, 0xB8, 0xBE, 0xD0, 0xDE, 0xF0, 0xFE, 0xFF} {
+		mcs[b] = -1
+	}
+	mcs[0xA8] = '¤'
+	mcs[0xD7] = 'Œ'
+	mcs[0xDD] = 'Ÿ'
+	mcs[0xF7] = 'œ'
+	mcs[0xFD] = 'ÿ'
+
+	ASCII = newByteCharset("ASCII", ascii)
+	Latin1 = newByteCharset("ISO 8859-1", latin1)
+	DECMCS = newByteCharset("DEC MCS", mcs)
+
+	RegisterCharset("ascii", ASCII)
+	RegisterCharset("latin1", Latin1)
+	RegisterCharset("dec-mcs", DECMCS)
+}
+
+var charsets sync.Map
+
+// RegisterCharset makes a Charset available to the charset option of vax
+// struct tags under the given name.
+func RegisterCharset(name string, cs Charset) {
+	charsets.Store(name, cs)
+}
+
+// characterField describes a fixed-length CHARACTER field.
+type characterField struct {
+	size    int
+	trim    bool
+	pad     byte
+	charset Charset
+	name    string // registered charset, looked up on each use when set
+}
+
+// lookup returns the Charset of cf. A charset named in a tag is looked up
+// when used, so that RegisterCharset may replace it after the tag is parsed.
+func (cf characterField) lookup() Charset {
+	if cf.name == "" {
+		return cf.charset
+	}
+	cs, _ := charsets.Load(cf.name)
+	return cs.(Charset)
+}
+
+// parseCharacterField parses the options of a char vax tag: the field size
+// followed by notrim, pad=space, pad=nul or charset=name.
+func parseCharacterField(opts []string) (characterField, error) {
+	cf := characterField{trim: true, pad: ' ', charset: DECMCS}
+	if len(opts) == 0 {
+		return cf, fmt.Errorf("vax tag \"char\" needs a field size")
+	}
+	size, err := strconv.Atoi(opts[0])
+	if err != nil || size < 1 {
+		return cf, fmt.Errorf("invalid CHARACTER field size %q", opts[0])
+	}
+	cf.size = size
+
+	for _, opt := range opts[1:] {
+		key, value, _ := strings.Cut(opt, "=")
+		switch {
+		case opt == "notrim":
+			cf.trim = false
+		case key == "pad" && value == "space":
+			cf.pad = ' '
+		case key == "pad" && value == "nul":
+			cf.pad = 0
+		case key == "charset":
+			if _, ok := charsets.Load(value); !ok {
+				return cf, fmt.Errorf("unknown charset %q", value)
+			}
+			cf.name = value
+		default:
+			return cf, fmt.Errorf("unknown vax tag option %q", opt)
+		}
+	}
+	return cf, nil
+}
+
+func (cf characterField) decode(b []byte) (string, error) {
+	if cf.trim {
+		for len(b) > 0 && b[len(b)-1] == cf.pad {
+			b = b[:len(b)-1]
+		}
+	}
+	return cf.lookup().Decode(b)
+}
+
+func (cf characterField) encode(b []byte, s string) error {
+	text, err := cf.lookup().Encode(s)
+	if err == nil && len(text) > len(b) {
+		err = fmt.Errorf("%d characters too long for CHARACTER*%d", len(text), len(b))
+	}
+
+	n := copy(b, text)
+	for i := range b[n:] {
+		b[n+i] = cf.pad
+	}
+	return err
+}
+
+// DecodeCharacter returns the text of a blank padded CHARACTER field, with
+// the trailing blanks removed. A nil Charset is DECMCS.
+func DecodeCharacter(b []byte, cs Charset) (string, error) {
+	if cs == nil {
+		cs = DECMCS
+	}
+	return characterField{trim: true, pad: ' ', charset: cs}.decode(b)
+}
+
+// EncodeCharacter stores s in the CHARACTER field b, padded with blanks. Text
+// too long for the field is truncated and reported as an error. A nil
+// Charset is DECMCS.
+func EncodeCharacter(b []byte, s string, cs Charset) error {
+	if cs == nil {
+		cs = DECMCS
+	}
+	return characterField{size: len(b), pad: ' ', charset: cs}.encode(b, s)
+}
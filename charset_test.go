@@ -0,0 +1,150 @@
+package vaxdata
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCharsets(t *testing.T) {
+	var tests = []struct {
+		cs   Charset
+		in   []byte
+		want string
+		ok   bool
+	}{
+		{ASCII, []byte("VMS"), "VMS", true},
+		{ASCII, []byte{'A', 0xC9}, "", false},
+		{Latin1, []byte{0xC9, 0xD7, 0xF7}, "É×÷", true},
+		{DECMCS, []byte{0xC9, 0xD7, 0xF7, 0xFD}, "ÉŒœÿ", true},
+		{DECMCS, []byte{0xA8}, "¤", true},
+		{DECMCS, []byte{0xA0}, "", false},
+		{DECMCS, []byte{0xFF}, "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.cs.Decode(tt.in)
+		if tt.ok != (err == nil) || got != tt.want {
+			t.Errorf("%v.Decode(%X) == %q, %v, want %q", tt.cs, tt.in, got, err, tt.want)
+			continue
+		}
+		if !tt.ok {
+			continue
+		}
+		b, err := tt.cs.Encode(got)
+		if err != nil || !bytes.Equal(b, tt.in) {
+			t.Errorf("%v.Encode(%q) == %X, %v, want %X", tt.cs, got, b, err, tt.in)
+		}
+	}
+
+	if _, err := DECMCS.Encode("€"); err == nil {
+		t.Errorf("DECMCS.Encode(%q) did not raise an error", "€")
+	}
+}
+
+type upperCharset struct{}
+
+func (upperCharset) Decode(b []byte) (string, error) { return strings.ToLower(string(b)), nil }
+func (upperCharset) Encode(s string) ([]byte, error) { return []byte(strings.ToUpper(s)), nil }
+
+func TestCharacterFields(t *testing.T) {
+	RegisterCharset("test-upper", upperCharset{})
+
+	type record struct {
+		Name  string `vax:"char,8"`
+		Raw   string `vax:"char,4,notrim"`
+		CStr  string `vax:"char,4,pad=nul"`
+		Upper string `vax:"char,3,charset=test-upper"`
+	}
+
+	in := []byte("JONES   AB  X\x00\x00\x00VMS")
+	var got record
+	if err := Read(bytes.NewReader(in), &got); err != nil {
+		t.Fatalf("Read raised unexpected error: %q", err)
+	}
+	want := record{"JONES", "AB  ", "X", "vms"}
+	if got != want {
+		t.Errorf("Read(%q) == %+v, want %+v", in, got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, got); err != nil {
+		t.Fatalf("Write raised unexpected error: %q", err)
+	}
+	if !bytes.Equal(buf.Bytes(), in) {
+		t.Errorf("Write(%+v) == %q, want %q", got, buf.Bytes(), in)
+	}
+
+	buf.Reset()
+	err := Write(&buf, record{Name: "WORCESTERSHIRE"})
+	if err == nil || err.(*FieldError).Path != "Name" {
+		t.Errorf("Write of an overlong Name raised %v, want a Name FieldError", err)
+	}
+	if got := buf.String()[:8]; got != "WORCESTE" {
+		t.Errorf("Write of an overlong Name stored %q, want %q", got, "WORCESTE")
+	}
+
+	for _, v := range []any{
+		&struct {
+			S string `vax:"char"`
+		}{},
+		&struct {
+			S string `vax:"char,4,charset=ebcdic"`
+		}{},
+		&struct {
+			N int `vax:"char,4"`
+		}{},
+		&struct {
+			S string `vax:"char,4abc"`
+		}{},
+		&struct {
+			S string `vax:"char,0x4"`
+		}{},
+		&struct{ S string }{},
+	} {
+		if err := Read(bytes.NewReader(make([]byte, 8)), v); err == nil {
+			t.Errorf("Read(%T) did not raise an error", v)
+		}
+	}
+}
+
+type lowerCharset struct{}
+
+func (lowerCharset) Decode(b []byte) (string, error) { return strings.ToUpper(string(b)), nil }
+func (lowerCharset) Encode(s string) ([]byte, error) { return []byte(strings.ToLower(s)), nil }
+
+func TestRegisterCharsetReplaces(t *testing.T) {
+	type record struct {
+		S string `vax:"char,3,charset=test-replace"`
+	}
+
+	RegisterCharset("test-replace", upperCharset{})
+	var buf bytes.Buffer
+	if err := Write(&buf, record{"vms"}); err != nil || buf.String() != "VMS" {
+		t.Fatalf("Write(vms) == %q, %v, want %q", buf.String(), err, "VMS")
+	}
+
+	// Tags already parsed use the Charset now registered under the name
+	RegisterCharset("test-replace", lowerCharset{})
+	buf.Reset()
+	if err := Write(&buf, record{"VMS"}); err != nil || buf.String() != "vms" {
+		t.Errorf("Write(VMS) == %q, %v, want %q", buf.String(), err, "vms")
+	}
+	var got record
+	if err := Read(bytes.NewReader([]byte("vax")), &got); err != nil || got.S != "VAX" {
+		t.Errorf("Read(vax) == %q, %v, want %q", got.S, err, "VAX")
+	}
+}
+
+func TestCharacter(t *testing.T) {
+	b := make([]byte, 6)
+	if err := EncodeCharacter(b, "Café", nil); err != nil {
+		t.Fatalf("EncodeCharacter raised unexpected error: %q", err)
+	}
+	if want := []byte{'C', 'a', 'f', 0xE9, ' ', ' '}; !bytes.Equal(b, want) {
+		t.Errorf("EncodeCharacter(%q) == %X, want %X", "Café", b, want)
+	}
+	if s, err := DecodeCharacter(b, nil); err != nil || s != "Café" {
+		t.Errorf("DecodeCharacter(%X) == %q, %v, want %q", b, s, err, "Café")
+	}
+}
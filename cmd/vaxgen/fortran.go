@@ -0,0 +1,828 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// statement is a FORTRAN statement with its continuation lines joined and
+// comments removed. Outside of quoted strings letters are in upper case and
+// blanks are removed, as blanks are not significant in FORTRAN.
+type statement struct {
+	text string
+	line int
+}
+
+// readStatements reads the fixed-form FORTRAN statements in r, which is named
+// file in errors, ignoring columns past width. DEC tab-format lines are also
+// accepted.
+func readStatements(r io.Reader, file string, width int) ([]statement, error) {
+	var stmts []statement
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if line == "" || strings.IndexByte("Cc*!Dd", line[0]) >= 0 {
+			continue
+		}
+
+		var text string
+		cont := false
+		if i := strings.IndexByte(line, '\t'); i >= 0 && i < 6 {
+			text = line[i+1:]
+			if text != "" && text[0] >= '1' && text[0] <= '9' {
+				cont, text = true, text[1:]
+			}
+		} else {
+			if len(line) > width {
+				line = line[:width]
+			}
+			if len(line) <= 6 {
+				continue
+			}
+			cont = line[5] != ' ' && line[5] != '0'
+			text = line[6:]
+		}
+
+		text = stripComment(text)
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if cont {
+			if len(stmts) == 0 {
+				return nil, fmt.Errorf("%s:%d: continuation line without a statement", file, n)
+			}
+			stmts[len(stmts)-1].text += text
+			continue
+		}
+		stmts = append(stmts, statement{text: text, line: n})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range stmts {
+		stmts[i].text = normalize(stmts[i].text)
+	}
+	return stmts, nil
+}
+
+// stripComment removes a trailing ! comment from a line.
+func stripComment(s string) string {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			quoted = !quoted
+		case '!':
+			if !quoted {
+				return s[:i]
+			}
+		}
+	}
+	return s
+}
+
+func normalize(s string) string {
+	var sb strings.Builder
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case quoted:
+		case c == ' ' || c == '\t':
+			continue
+		case c >= 'a' && c <= 'z':
+			c -= 'a' - 'A'
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// fortranParser builds record layouts from VAX FORTRAN STRUCTURE
+// declarations and COMMON blocks.
+type fortranParser struct {
+	file  string
+	line  int
+	real8 Kind // DFloat or GFloat, for REAL*8 and DOUBLE PRECISION
+
+	records []*Record
+	structs map[string]*Record
+	commons map[string]*Record
+	scopes  []scope
+
+	// declarations of the current program unit
+	vars     map[string]*Field
+	bad      map[string]string // variables of types without a layout
+	params   map[string]int
+	implicit [26]implicitType // by initial letter
+}
+
+// implicitType is the type given to undeclared names by an IMPLICIT
+// statement or the default rules.
+type implicitType struct {
+	field Field
+	err   error // the type has no layout
+	none  bool  // IMPLICIT NONE
+}
+
+// scope is an open STRUCTURE, UNION or MAP block.
+type scope struct {
+	keyword string // STRUCTURE, UNION or MAP
+	record  *Record
+	union   *Field
+	fields  *[]*Field // where declarations are added
+	decl    []*Field  // fields declared by a nested STRUCTURE
+}
+
+func newFortranParser(real8 Kind) *fortranParser {
+	p := &fortranParser{
+		real8:   real8,
+		structs: make(map[string]*Record),
+		commons: make(map[string]*Record),
+	}
+	p.endUnit()
+	return p
+}
+
+// endUnit forgets the declarations of the current program unit.
+func (p *fortranParser) endUnit() {
+	p.vars = make(map[string]*Field)
+	p.bad = make(map[string]string)
+	p.params = make(map[string]int)
+
+	// REAL unless the name begins with I to N
+	real, _ := p.typeOf("REAL", 0, false)
+	integer, _ := p.typeOf("INTEGER", 0, false)
+	for i := range p.implicit {
+		p.implicit[i] = implicitType{field: real}
+		if 'A'+i >= 'I' && 'A'+i <= 'N' {
+			p.implicit[i].field = integer
+		}
+	}
+}
+
+func (p *fortranParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", p.file, p.line, fmt.Sprintf(format, args...))
+}
+
+// parse reads the declarations in r, which is named file in errors.
+// Structures declared in earlier files may be used by later ones.
+func (p *fortranParser) parse(r io.Reader, file string, width int) error {
+	p.file = file
+	stmts, err := readStatements(r, file, width)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range stmts {
+		p.line = s.line
+		if err := p.statement(s.text); err != nil {
+			return err
+		}
+	}
+	if len(p.scopes) > 0 {
+		return p.errorf("missing END %s", p.scopes[len(p.scopes)-1].keyword)
+	}
+	return nil
+}
+
+var typeKeywords = []string{
+	"DOUBLEPRECISION", "DOUBLECOMPLEX", "CHARACTER", "INTEGER", "LOGICAL", "COMPLEX", "REAL", "BYTE",
+}
+
+func (p *fortranParser) statement(s string) error {
+	var top *scope
+	if len(p.scopes) > 0 {
+		top = &p.scopes[len(p.scopes)-1]
+	}
+
+	if isAssignment(s) {
+		if top != nil {
+			return p.errorf("unexpected statement in %s", top.keyword)
+		}
+		return nil
+	}
+
+	switch {
+	case s == "ENDSTRUCTURE" || s == "ENDUNION" || s == "ENDMAP":
+		return p.end(top, s[3:])
+	case strings.HasPrefix(s, "STRUCTURE"):
+		return p.structure(top, s[len("STRUCTURE"):])
+	case s == "UNION":
+		if top == nil || top.keyword == "UNION" {
+			return p.errorf("UNION outside of a STRUCTURE or MAP")
+		}
+		u := &Field{Kind: Union, Decl: "UNION"}
+		*top.fields = append(*top.fields, u)
+		p.scopes = append(p.scopes, scope{keyword: "UNION", record: top.record, union: u})
+		return nil
+	case s == "MAP":
+		if top == nil || top.keyword != "UNION" {
+			return p.errorf("MAP outside of a UNION")
+		}
+		u := top.union
+		u.Maps = append(u.Maps, nil)
+		p.scopes = append(p.scopes, scope{keyword: "MAP", record: top.record, fields: &u.Maps[len(u.Maps)-1]})
+		return nil
+	}
+
+	if top != nil && top.keyword == "UNION" {
+		return p.errorf("unexpected statement in UNION")
+	}
+
+	switch {
+	case strings.HasPrefix(s, "RECORD/"):
+		return p.record(top, s[len("RECORD"):])
+	case top != nil:
+		fields, ok, err := p.typeStatement(s)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return p.errorf("unexpected statement in %s", top.keyword)
+		}
+		*top.fields = append(*top.fields, fields...)
+		return nil
+	case strings.HasPrefix(s, "COMMON"):
+		return p.common(s[len("COMMON"):])
+	case strings.HasPrefix(s, "DIMENSION"):
+		return p.dimension(s[len("DIMENSION"):])
+	case strings.HasPrefix(s, "PARAMETER("):
+		return p.parameter(s[len("PARAMETER"):])
+	case strings.HasPrefix(s, "IMPLICIT"):
+		return p.implicitStatement(s[len("IMPLICIT"):])
+	case s == "END":
+		p.endUnit()
+		return nil
+	}
+
+	fields, ok, err := p.typeStatement(s)
+	if !ok {
+		return nil
+	}
+	if err != nil {
+		// only an error if the variables are placed in COMMON
+		for _, e := range splitList(s[len(typeKeyword(s)):]) {
+			if name, _ := cutName(strings.TrimLeft(e, "*0123456789()")); name != "" {
+				p.bad[name] = err.Error()
+			}
+		}
+		return nil
+	}
+	for _, f := range fields {
+		p.vars[f.Name] = f
+	}
+	return nil
+}
+
+// isAssignment reports whether s is an assignment, DO or other executable
+// statement with = outside of parentheses.
+func isAssignment(s string) bool {
+	if strings.HasPrefix(s, "PARAMETER(") {
+		return false
+	}
+	depth, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == '=' && depth == 0:
+			return true
+		}
+	}
+	return false
+}
+
+func (p *fortranParser) end(top *scope, keyword string) error {
+	if top == nil || top.keyword != keyword {
+		return p.errorf("END %s without %s", keyword, keyword)
+	}
+	p.scopes = p.scopes[:len(p.scopes)-1]
+	if keyword != "STRUCTURE" {
+		return nil
+	}
+
+	r := top.record
+	if r.Name != "" && !strings.HasPrefix(r.Name, "%") {
+		p.structs[r.Name] = r
+	}
+	if len(p.scopes) > 0 {
+		outer := &p.scopes[len(p.scopes)-1]
+		*outer.fields = append(*outer.fields, top.decl...)
+	}
+	return nil
+}
+
+func (p *fortranParser) structure(top *scope, s string) error {
+	var name string
+	if strings.HasPrefix(s, "/") {
+		end := strings.IndexByte(s[1:], '/')
+		if end < 0 {
+			return p.errorf("malformed STRUCTURE name")
+		}
+		name, s = s[1:end+1], s[end+2:]
+		if !isName(name) {
+			return p.errorf("invalid STRUCTURE name %q", name)
+		}
+		if _, ok := p.structs[name]; ok {
+			return p.errorf("STRUCTURE /%s/ is already declared", name)
+		}
+	}
+
+	decl := fmt.Sprintf("STRUCTURE /%s/", name)
+	r := &Record{Name: name, Decl: decl}
+	var fields []*Field
+	if s != "" {
+		if top == nil {
+			return p.errorf("field list on a top level STRUCTURE")
+		}
+		for _, e := range splitList(s) {
+			f, err := p.entity(e, Field{Kind: Struct, Record: r})
+			if err != nil {
+				return err
+			}
+			fields = append(fields, f)
+		}
+	}
+
+	switch {
+	case top == nil && name == "":
+		return p.errorf("top level STRUCTURE needs a name")
+	case name == "" && len(fields) == 0:
+		return p.errorf("nested STRUCTURE needs a name or field list")
+	case name == "":
+		// named for the type and first field declared with it
+		r.Name = "%" + top.record.Name + "_" + fields[0].Name
+		r.Decl = fmt.Sprintf("the STRUCTURE declaring %s in %s", fields[0].Name, top.record.Decl)
+		decl = "STRUCTURE"
+	}
+	for _, f := range fields {
+		f.Decl = decl
+	}
+
+	p.records = append(p.records, r)
+	p.scopes = append(p.scopes, scope{keyword: "STRUCTURE", record: r, fields: &r.Fields, decl: fields})
+	return nil
+}
+
+func (p *fortranParser) record(top *scope, s string) error {
+	end := strings.IndexByte(s[1:], '/')
+	if end < 0 {
+		return p.errorf("malformed RECORD statement")
+	}
+	name := s[1 : end+1]
+	r, ok := p.structs[name]
+	if !ok {
+		return p.errorf("unknown STRUCTURE /%s/", name)
+	}
+
+	for _, e := range splitList(s[end+2:]) {
+		f, err := p.entity(e, Field{Kind: Struct, Record: r, Decl: fmt.Sprintf("RECORD /%s/", name)})
+		if err != nil {
+			return err
+		}
+		if top != nil {
+			*top.fields = append(*top.fields, f)
+		} else {
+			p.vars[f.Name] = f
+		}
+	}
+	return nil
+}
+
+// typeKeyword returns the type keyword beginning s, if any.
+func typeKeyword(s string) string {
+	for _, kw := range typeKeywords {
+		if strings.HasPrefix(s, kw) {
+			return kw
+		}
+	}
+	return ""
+}
+
+// typeStatement parses a type declaration statement such as
+// "INTEGER*2 COUNT, FLAGS(4)". ok is false if s is not a type declaration.
+func (p *fortranParser) typeStatement(s string) (fields []*Field, ok bool, err error) {
+	kw := typeKeyword(s)
+	if kw == "" {
+		return nil, false, nil
+	}
+	rest := s[len(kw):]
+
+	length, explicit := 0, false
+	if strings.HasPrefix(rest, "*") {
+		if length, rest, err = p.length(rest[1:]); err != nil {
+			return nil, true, err
+		}
+		explicit = true
+	}
+	if strings.HasPrefix(rest, "FUNCTION") || rest == "" {
+		return nil, false, nil
+	}
+
+	for _, e := range splitList(rest) {
+		n, x := length, explicit
+		name, tail := cutName(e)
+		if strings.HasPrefix(tail, "*") {
+			if n, tail, err = p.length(tail[1:]); err != nil {
+				return nil, true, err
+			}
+			x = true
+		}
+
+		dims := ""
+		if strings.HasPrefix(tail, "(") {
+			end := strings.IndexByte(tail, ')')
+			if end < 0 {
+				return nil, true, p.errorf("malformed dimensions of %s", name)
+			}
+			dims, tail = tail[:end+1], tail[end+1:]
+		}
+		if strings.HasPrefix(tail, "*") && dims != "" {
+			if n, tail, err = p.length(tail[1:]); err != nil {
+				return nil, true, err
+			}
+			x = true
+		}
+		if strings.HasPrefix(tail, "/") && strings.HasSuffix(tail, "/") {
+			tail = "" // initial value
+		}
+		if tail != "" {
+			return nil, true, p.errorf("malformed declaration %q", e)
+		}
+
+		t, err := p.typeOf(kw, n, x)
+		if err != nil {
+			return nil, true, err
+		}
+		f, err := p.entity(name+dims, t)
+		if err != nil {
+			return nil, true, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, true, nil
+}
+
+// length parses the length following the * of a type, returning the rest
+// of s.
+func (p *fortranParser) length(s string) (int, string, error) {
+	if strings.HasPrefix(s, "(") {
+		end := strings.IndexByte(s, ')')
+		if end < 0 {
+			return 0, "", p.errorf("malformed length")
+		}
+		if s[1:end] == "*" {
+			return 0, "", p.errorf("assumed length CHARACTER*(*) has no layout")
+		}
+		n, err := p.value(s[1:end])
+		return n, s[end+1:], err
+	}
+
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return 0, "", p.errorf("malformed length")
+	}
+	n, _ := strconv.Atoi(s[:i])
+	return n, s[i:], nil
+}
+
+// typeOf returns a template Field for the FORTRAN type kw*n, where n is only
+// meaningful if explicit is set.
+func (p *fortranParser) typeOf(kw string, n int, explicit bool) (Field, error) {
+	defaults := map[string]int{"INTEGER": 4, "LOGICAL": 4, "REAL": 4, "CHARACTER": 1, "BYTE": 1}
+	if !explicit {
+		n = defaults[kw]
+	}
+	decl := fmt.Sprintf("%s*%d", kw, n)
+
+	switch kw {
+	case "BYTE":
+		if !explicit {
+			return Field{Kind: Integer, Len: 1, Decl: "BYTE"}, nil
+		}
+	case "INTEGER", "LOGICAL":
+		if n == 1 || n == 2 || n == 4 || n == 8 {
+			kind := Integer
+			if kw == "LOGICAL" {
+				kind = Logical
+			}
+			return Field{Kind: kind, Len: n, Decl: decl}, nil
+		}
+	case "REAL":
+		switch n {
+		case 4:
+			return Field{Kind: FFloat, Decl: decl}, nil
+		case 8:
+			return Field{Kind: p.real8, Decl: decl}, nil
+		}
+	case "DOUBLEPRECISION":
+		if !explicit {
+			return Field{Kind: p.real8, Decl: "DOUBLE PRECISION"}, nil
+		}
+	case "CHARACTER":
+		if n > 0 {
+			return Field{Kind: Character, Len: n, Decl: decl}, nil
+		}
+	}
+	if !explicit {
+		decl = kw
+	}
+	return Field{}, p.errorf("%s is not supported", decl)
+}
+
+// entity parses a name with optional dimensions, such as "X(3,0:4)", as a
+// field of the template type t.
+func (p *fortranParser) entity(s string, t Field) (*Field, error) {
+	name, rest := cutName(s)
+	if name == "" {
+		return nil, p.errorf("malformed name %q", s)
+	}
+	if name == "%FILL" {
+		name = ""
+	}
+
+	f := t
+	f.Name = name
+	if rest == "" {
+		return &f, nil
+	}
+	if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+		return nil, p.errorf("malformed declaration %q", s)
+	}
+	dims, err := p.dimensions(rest[1 : len(rest)-1])
+	if err != nil {
+		return nil, err
+	}
+	f.Dims = dims
+	return &f, nil
+}
+
+// dimensions parses the bounds of an array, returning the extents in Go
+// order. FORTRAN arrays are stored with the first subscript varying fastest,
+// so X(3,4) is a [4][3] array.
+func (p *fortranParser) dimensions(s string) ([]int, error) {
+	var dims []int
+	for _, d := range strings.Split(s, ",") {
+		lo, hi := 1, 0
+		var err error
+		if l, h, ok := strings.Cut(d, ":"); ok {
+			if lo, err = p.value(l); err != nil {
+				return nil, err
+			}
+			d = h
+		}
+		if hi, err = p.value(d); err != nil {
+			return nil, err
+		}
+		if hi < lo {
+			return nil, p.errorf("invalid dimension %q", s)
+		}
+		dims = append([]int{hi - lo + 1}, dims...)
+	}
+	return dims, nil
+}
+
+// value returns the value of an integer constant or PARAMETER.
+func (p *fortranParser) value(s string) (int, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	if n, ok := p.params[s]; ok {
+		return n, nil
+	}
+	return 0, p.errorf("%q is not an integer constant", s)
+}
+
+func (p *fortranParser) parameter(s string) error {
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return nil
+	}
+	for _, def := range splitList(s[1 : len(s)-1]) {
+		name, expr, ok := strings.Cut(def, "=")
+		if !ok {
+			continue
+		}
+		if n, err := p.value(expr); err == nil {
+			p.params[name] = n
+		}
+	}
+	return nil
+}
+
+func (p *fortranParser) dimension(s string) error {
+	for _, e := range splitList(s) {
+		f, err := p.entity(e, Field{})
+		if err != nil {
+			return err
+		}
+		if f.Name == "" {
+			return p.errorf("%%FILL is not allowed in DIMENSION")
+		}
+		v, err := p.variable(f.Name)
+		if err != nil {
+			return err
+		}
+		v.Dims = f.Dims
+	}
+	return nil
+}
+
+// variable returns the declaration of the named variable, implicitly typed
+// if it was not declared.
+func (p *fortranParser) variable(name string) (*Field, error) {
+	if msg, ok := p.bad[name]; ok {
+		return nil, errors.New(msg)
+	}
+	if v, ok := p.vars[name]; ok {
+		return v, nil
+	}
+
+	it := p.implicit[name[0]-'A']
+	switch {
+	case it.none:
+		return nil, p.errorf("%s is not declared, and IMPLICIT NONE is in effect", name)
+	case it.err != nil:
+		return nil, it.err
+	}
+	t := it.field
+	t.Name = name
+	p.vars[name] = &t
+	return &t, nil
+}
+
+// implicitStatement parses the rest of an IMPLICIT statement, such as
+// "REAL*8(A-H,O-Z),INTEGER*2(I-N)" or "NONE".
+func (p *fortranParser) implicitStatement(s string) error {
+	if s == "NONE" {
+		for i := range p.implicit {
+			p.implicit[i] = implicitType{none: true}
+		}
+		return nil
+	}
+
+	for _, spec := range splitList(s) {
+		kw := typeKeyword(spec)
+		if kw == "" {
+			return p.errorf("IMPLICIT %s is not supported", spec)
+		}
+		rest := spec[len(kw):]
+
+		length, explicit := 0, false
+		if strings.HasPrefix(rest, "*") {
+			var err error
+			if length, rest, err = p.length(rest[1:]); err != nil {
+				return err
+			}
+			explicit = true
+		}
+		if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+			return p.errorf("malformed IMPLICIT statement")
+		}
+		t, err := p.typeOf(kw, length, explicit)
+
+		for _, r := range strings.Split(rest[1:len(rest)-1], ",") {
+			lo, hi, ok := strings.Cut(r, "-")
+			if !ok {
+				hi = lo
+			}
+			if len(lo) != 1 || len(hi) != 1 || lo[0] < 'A' || hi[0] > 'Z' || lo[0] > hi[0] {
+				return p.errorf("invalid IMPLICIT letter range %q", r)
+			}
+			for c := lo[0]; c <= hi[0]; c++ {
+				p.implicit[c-'A'] = implicitType{field: t, err: err}
+			}
+		}
+	}
+	return nil
+}
+
+func (p *fortranParser) common(s string) error {
+	block := ""
+	for s != "" {
+		if s[0] == '/' {
+			end := strings.IndexByte(s[1:], '/')
+			if end < 0 {
+				return p.errorf("malformed COMMON block name")
+			}
+			block, s = s[1:end+1], s[end+2:]
+			continue
+		}
+
+		var item string
+		item, s = cutItem(s)
+		if item == "" {
+			continue
+		}
+		e, err := p.entity(item, Field{})
+		if err != nil {
+			return err
+		}
+		if e.Name == "" {
+			return p.errorf("%%FILL is not allowed in COMMON")
+		}
+		v, err := p.variable(e.Name)
+		if err != nil {
+			return err
+		}
+		f := *v
+		if e.Dims != nil {
+			if f.Dims != nil {
+				return p.errorf("%s is already dimensioned", e.Name)
+			}
+			f.Dims = e.Dims
+		}
+
+		r, ok := p.commons[block]
+		if !ok {
+			r = &Record{Name: block + "%COMMON", Decl: fmt.Sprintf("COMMON /%s/", block)}
+			if block == "" {
+				r.Name, r.Decl = "BLANK%COMMON", "blank COMMON"
+			}
+			p.commons[block] = r
+			p.records = append(p.records, r)
+		}
+		r.Fields = append(r.Fields, &f)
+	}
+	return nil
+}
+
+// cutItem returns the COMMON list item at the start of s and the rest of s,
+// which begins after the comma or at the / ending the item.
+func cutItem(s string) (item, rest string) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				return s[:i], s[i+1:]
+			}
+		case '/':
+			if depth == 0 {
+				return s[:i], s[i:]
+			}
+		}
+	}
+	return s, ""
+}
+
+// splitList splits s at the commas outside of parentheses, quotes and
+// /initial values/.
+func splitList(s string) []string {
+	var list []string
+	depth, quoted, slashed := 0, false, false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == '/' && depth == 0:
+			slashed = !slashed
+		case c == ',' && depth == 0 && !slashed:
+			list = append(list, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(list, s[start:])
+}
+
+// cutName returns the name at the start of s and the rest of s.
+func cutName(s string) (name, rest string) {
+	if strings.HasPrefix(s, "%FILL") {
+		return "%FILL", s[len("%FILL"):]
+	}
+	i := 0
+	for i < len(s) && (s[i] >= 'A' && s[i] <= 'Z' || i > 0 && (s[i] >= '0' && s[i] <= '9' || s[i] == '_' || s[i] == '$')) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isName(s string) bool {
+	name, rest := cutName(s)
+	return name != "" && name != "%FILL" && rest == ""
+}
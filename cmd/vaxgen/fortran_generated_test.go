@@ -0,0 +1,332 @@
+// Code generated by vaxgen from sample.for; DO NOT EDIT.
+
+package main
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/sixlettervariables/vaxdata"
+)
+
+// Point is the layout of STRUCTURE /POINT/.
+type Point struct {
+	X float32 // REAL*4
+	Y float32 // REAL*4
+}
+
+// VAXSize returns the size in bytes of the VAX representation of a Point.
+func (*Point) VAXSize() int { return 8 }
+
+// DecodeVAX decodes r from the VAX representation at the start of b. Every
+// field is decoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *Point) DecodeVAX(b []byte) error {
+	if len(b) < 8 {
+		return io.ErrShortBuffer
+	}
+	if err := r.decodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// EncodeVAX stores the VAX representation of r at the start of b. Every
+// field is encoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *Point) EncodeVAX(b []byte) error {
+	if len(b) < 8 {
+		return io.ErrShortBuffer
+	}
+	clear(b[:8])
+	if err := r.encodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Point) decodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	if r.X, e = vaxdata.F.Float32(b[0:]); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "X", Offset: 0, Err: e}
+	}
+	if r.Y, e = vaxdata.F.Float32(b[4:]); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Y", Offset: 4, Err: e}
+	}
+	return err
+}
+
+func (r *Point) encodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	if e = vaxdata.F.PutFloat32(b[0:], r.X); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "X", Offset: 0, Err: e}
+	}
+	if e = vaxdata.F.PutFloat32(b[4:], r.Y); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Y", Offset: 4, Err: e}
+	}
+	return err
+}
+
+// Sample is the layout of STRUCTURE /SAMPLE/.
+type Sample struct {
+	Id      int32         // INTEGER*4
+	Valid   bool          // LOGICAL*2
+	Rate    float64       // REAL*8
+	Station string        // CHARACTER*8
+	Origin  Point         // RECORD /POINT/
+	Track   [2]Point      // RECORD /POINT/
+	Values  [2][3]float32 // REAL*4
+	Code    int32         // INTEGER*4
+	Flags   [4]int8       // BYTE
+	Union1  int           // member of UNION 1 to encode, from 0
+	Sensor  SampleSensor  // STRUCTURE
+}
+
+// VAXSize returns the size in bytes of the VAX representation of a Sample.
+func (*Sample) VAXSize() int { return 82 }
+
+// DecodeVAX decodes r from the VAX representation at the start of b. Every
+// field is decoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *Sample) DecodeVAX(b []byte) error {
+	if len(b) < 82 {
+		return io.ErrShortBuffer
+	}
+	if err := r.decodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// EncodeVAX stores the VAX representation of r at the start of b. Every
+// field is encoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *Sample) EncodeVAX(b []byte) error {
+	if len(b) < 82 {
+		return io.ErrShortBuffer
+	}
+	clear(b[:82])
+	if err := r.encodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Sample) decodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	r.Id = int32(vaxdata.F.Uint32(b[0:]))
+	r.Valid = b[4]&1 != 0
+	if r.Rate, e = vaxdata.F.Float64(b[8:]); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Rate", Offset: 8, Err: e}
+	}
+	if r.Station, e = vaxdata.DecodeCharacter(b[16:24], nil); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Station", Offset: 16, Err: e}
+	}
+	if fe := r.Origin.decodeVAX(b[24:]); fe != nil && err == nil {
+		fe.Path = "Origin." + fe.Path
+		fe.Offset += 24
+		err = fe
+	}
+	for i0 := range r.Track {
+		o := 32 + i0*8
+		if fe := r.Track[i0].decodeVAX(b[o:]); fe != nil && err == nil {
+			fe.Path = "Track[" + strconv.Itoa(i0) + "]." + fe.Path
+			fe.Offset += int64(o)
+			err = fe
+		}
+	}
+	for i0 := range r.Values {
+		for i1 := range r.Values[i0] {
+			o := 48 + (i0*3+i1)*4
+			if r.Values[i0][i1], e = vaxdata.F.Float32(b[o:]); e != nil && err == nil {
+				err = &vaxdata.FieldError{Path: "Values[" + strconv.Itoa(i0) + "][" + strconv.Itoa(i1) + "]", Offset: int64(o), Err: e}
+			}
+		}
+	}
+	r.Code = int32(vaxdata.F.Uint32(b[72:]))
+	for i0 := range r.Flags {
+		o := 72 + i0
+		r.Flags[i0] = int8(b[o])
+	}
+	if fe := r.Sensor.decodeVAX(b[76:]); fe != nil && err == nil {
+		fe.Path = "Sensor." + fe.Path
+		fe.Offset += 76
+		err = fe
+	}
+	return err
+}
+
+func (r *Sample) encodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	vaxdata.F.PutUint32(b[0:], uint32(r.Id))
+	if r.Valid {
+		vaxdata.F.PutUint16(b[4:], ^uint16(0))
+	}
+	if e = vaxdata.F.PutFloat64(b[8:], r.Rate); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Rate", Offset: 8, Err: e}
+	}
+	if e = vaxdata.EncodeCharacter(b[16:24], r.Station, nil); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Station", Offset: 16, Err: e}
+	}
+	if fe := r.Origin.encodeVAX(b[24:]); fe != nil && err == nil {
+		fe.Path = "Origin." + fe.Path
+		fe.Offset += 24
+		err = fe
+	}
+	for i0 := range r.Track {
+		o := 32 + i0*8
+		if fe := r.Track[i0].encodeVAX(b[o:]); fe != nil && err == nil {
+			fe.Path = "Track[" + strconv.Itoa(i0) + "]." + fe.Path
+			fe.Offset += int64(o)
+			err = fe
+		}
+	}
+	for i0 := range r.Values {
+		for i1 := range r.Values[i0] {
+			o := 48 + (i0*3+i1)*4
+			if e = vaxdata.F.PutFloat32(b[o:], r.Values[i0][i1]); e != nil && err == nil {
+				err = &vaxdata.FieldError{Path: "Values[" + strconv.Itoa(i0) + "][" + strconv.Itoa(i1) + "]", Offset: int64(o), Err: e}
+			}
+		}
+	}
+	switch r.Union1 {
+	case 0:
+		vaxdata.F.PutUint32(b[72:], uint32(r.Code))
+	case 1:
+		for i0 := range r.Flags {
+			o := 72 + i0
+			b[o] = byte(r.Flags[i0])
+		}
+	}
+	if fe := r.Sensor.encodeVAX(b[76:]); fe != nil && err == nil {
+		fe.Path = "Sensor." + fe.Path
+		fe.Offset += 76
+		err = fe
+	}
+	return err
+}
+
+// SampleSensor is the layout of the STRUCTURE declaring SENSOR in STRUCTURE /SAMPLE/.
+type SampleSensor struct {
+	Kind string // CHARACTER*4
+	Gain int16  // INTEGER*2
+}
+
+// VAXSize returns the size in bytes of the VAX representation of a SampleSensor.
+func (*SampleSensor) VAXSize() int { return 6 }
+
+// DecodeVAX decodes r from the VAX representation at the start of b. Every
+// field is decoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *SampleSensor) DecodeVAX(b []byte) error {
+	if len(b) < 6 {
+		return io.ErrShortBuffer
+	}
+	if err := r.decodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// EncodeVAX stores the VAX representation of r at the start of b. Every
+// field is encoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *SampleSensor) EncodeVAX(b []byte) error {
+	if len(b) < 6 {
+		return io.ErrShortBuffer
+	}
+	clear(b[:6])
+	if err := r.encodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *SampleSensor) decodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	if r.Kind, e = vaxdata.DecodeCharacter(b[0:4], nil); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Kind", Offset: 0, Err: e}
+	}
+	r.Gain = int16(vaxdata.F.Uint16(b[4:]))
+	return err
+}
+
+func (r *SampleSensor) encodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	if e = vaxdata.EncodeCharacter(b[0:4], r.Kind, nil); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Kind", Offset: 0, Err: e}
+	}
+	vaxdata.F.PutUint16(b[4:], uint16(r.Gain))
+	return err
+}
+
+// StatsCommon is the layout of COMMON /STATS/.
+type StatsCommon struct {
+	Nread int16      // INTEGER*2
+	Total [2]float32 // REAL*4
+	Last  Sample     // RECORD /SAMPLE/
+}
+
+// VAXSize returns the size in bytes of the VAX representation of a StatsCommon.
+func (*StatsCommon) VAXSize() int { return 92 }
+
+// DecodeVAX decodes r from the VAX representation at the start of b. Every
+// field is decoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *StatsCommon) DecodeVAX(b []byte) error {
+	if len(b) < 92 {
+		return io.ErrShortBuffer
+	}
+	if err := r.decodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// EncodeVAX stores the VAX representation of r at the start of b. Every
+// field is encoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *StatsCommon) EncodeVAX(b []byte) error {
+	if len(b) < 92 {
+		return io.ErrShortBuffer
+	}
+	clear(b[:92])
+	if err := r.encodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *StatsCommon) decodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	r.Nread = int16(vaxdata.F.Uint16(b[0:]))
+	for i0 := range r.Total {
+		o := 2 + i0*4
+		if r.Total[i0], e = vaxdata.F.Float32(b[o:]); e != nil && err == nil {
+			err = &vaxdata.FieldError{Path: "Total[" + strconv.Itoa(i0) + "]", Offset: int64(o), Err: e}
+		}
+	}
+	if fe := r.Last.decodeVAX(b[10:]); fe != nil && err == nil {
+		fe.Path = "Last." + fe.Path
+		fe.Offset += 10
+		err = fe
+	}
+	return err
+}
+
+func (r *StatsCommon) encodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	vaxdata.F.PutUint16(b[0:], uint16(r.Nread))
+	for i0 := range r.Total {
+		o := 2 + i0*4
+		if e = vaxdata.F.PutFloat32(b[o:], r.Total[i0]); e != nil && err == nil {
+			err = &vaxdata.FieldError{Path: "Total[" + strconv.Itoa(i0) + "]", Offset: int64(o), Err: e}
+		}
+	}
+	if fe := r.Last.encodeVAX(b[10:]); fe != nil && err == nil {
+		fe.Path = "Last." + fe.Path
+		fe.Offset += 10
+		err = fe
+	}
+	return err
+}
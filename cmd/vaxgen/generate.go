@@ -0,0 +1,445 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// generator writes the Go source for record layouts.
+type generator struct {
+	out     bytes.Buffer
+	body    bytes.Buffer // body of the method being generated
+	strconv bool         // whether strconv is imported
+	faults  bool         // whether the method body uses e
+	unions  int          // UNION's numbered so far in the record
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+// generate returns the Go source of package pkg for the records, which were
+// read from the named source files.
+func generate(pkg string, sources []string, records []*Record) ([]byte, error) {
+	if err := checkNames(records); err != nil {
+		return nil, err
+	}
+	g := new(generator)
+	for _, r := range records {
+		g.record(r)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by vaxgen from %s; DO NOT EDIT.\n\n", strings.Join(sources, ", "))
+	fmt.Fprintf(&src, "package %s\n\nimport (\n\"io\"\n", pkg)
+	if g.strconv {
+		fmt.Fprintf(&src, "\"strconv\"\n")
+	}
+	fmt.Fprintf(&src, "\n\"github.com/sixlettervariables/vaxdata\"\n)\n")
+	src.Write(g.out.Bytes())
+	return formatSource(src.Bytes())
+}
+
+// generateTags returns the Go source of package pkg declaring the records as
+// structs with vax tags, to be converted by vaxdata.Read and vaxdata.Write.
+// Packed decimal fields are left as bytes, and records with a UNION cannot
+// be declared.
+func generateTags(pkg string, sources []string, records []*Record) ([]byte, error) {
+	if err := checkNames(records); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	imported := false
+	for _, r := range records {
+		name := goName(r.Name)
+		fmt.Fprintf(&out, "\n// %s is the layout of %s.\ntype %s struct {\n", name, r.Decl, name)
+		for _, f := range r.Fields {
+			if f.Kind == Union {
+				return nil, fmt.Errorf("%s has a UNION, which needs generated methods rather than tags", r.Decl)
+			}
+			if f.Name == "" {
+				fmt.Fprintf(&out, "_ [%d]byte\n", f.Size())
+				continue
+			}
+
+			typ, tag, decl := goType(f), vaxTag(f), f.Decl
+			if f.Kind == Decimal {
+				typ = strings.TrimSuffix(typ, "vaxdata.Decimal") + fmt.Sprintf("[%d]byte", f.Len)
+				decl += ", see vaxdata.DecodePackedDecimal"
+			}
+			if tag != "" {
+				tag = " `vax:\"" + tag + "\"`"
+			}
+			imported = imported || strings.Contains(typ, "vaxdata.")
+			fmt.Fprintf(&out, "%s %s%s // %s\n", goName(f.Name), typ, tag, decl)
+		}
+		fmt.Fprintf(&out, "}\n")
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by vaxgen from %s; DO NOT EDIT.\n\n", strings.Join(sources, ", "))
+	fmt.Fprintf(&src, "package %s\n", pkg)
+	if imported {
+		fmt.Fprintf(&src, "\nimport \"github.com/sixlettervariables/vaxdata\"\n")
+	}
+	src.Write(out.Bytes())
+	return formatSource(src.Bytes())
+}
+
+// vaxTag returns the vax struct tag of the field, if it needs one.
+func vaxTag(f *Field) string {
+	switch f.Kind {
+	case Integer, Logical:
+		return map[int]string{1: "b", 2: "w", 4: "l", 8: "q"}[f.Len]
+	case FFloat:
+		return "f"
+	case DFloat:
+		return "d"
+	case GFloat:
+		return "g"
+	case Character:
+		return fmt.Sprintf("char,%d", f.Len)
+	}
+	return ""
+}
+
+// checkNames reports records whose Go names collide.
+func checkNames(records []*Record) error {
+	names := make(map[string]string)
+	for _, r := range records {
+		name := goName(r.Name)
+		if prev, ok := names[name]; ok {
+			return fmt.Errorf("%s and %s are both named %s in Go", prev, r.Decl, name)
+		}
+		names[name] = r.Decl
+	}
+	return nil
+}
+
+func formatSource(src []byte) ([]byte, error) {
+	b, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return b, nil
+}
+
+func (g *generator) record(r *Record) {
+	name, size := goName(r.Name), r.Size()
+
+	fmt.Fprintf(&g.out, "\n// %s is the layout of %s.\ntype %s struct {\n", name, r.Decl, name)
+	g.unions = 0
+	g.body.Reset()
+	g.structFields(r.Fields)
+	g.out.Write(g.body.Bytes())
+	fmt.Fprintf(&g.out, "}\n")
+
+	fmt.Fprintf(&g.out, `
+// VAXSize returns the size in bytes of the VAX representation of a %[1]s.
+func (*%[1]s) VAXSize() int { return %[2]d }
+
+// DecodeVAX decodes r from the VAX representation at the start of b. Every
+// field is decoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *%[1]s) DecodeVAX(b []byte) error {
+	if len(b) < %[2]d {
+		return io.ErrShortBuffer
+	}
+	if err := r.decodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// EncodeVAX stores the VAX representation of r at the start of b. Every
+// field is encoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *%[1]s) EncodeVAX(b []byte) error {
+	if len(b) < %[2]d {
+		return io.ErrShortBuffer
+	}
+	clear(b[:%[2]d])
+	if err := r.encodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+`, name, size)
+
+	for _, decode := range []bool{true, false} {
+		g.unions, g.faults = 0, false
+		g.body.Reset()
+		g.fields(decode, r.Fields, 0)
+
+		method := "encodeVAX"
+		if decode {
+			method = "decodeVAX"
+		}
+		fmt.Fprintf(&g.out, "\nfunc (r *%s) %s(b []byte) (err *vaxdata.FieldError) {\n", name, method)
+		if g.faults {
+			fmt.Fprintf(&g.out, "var e error\n")
+		}
+		g.out.Write(g.body.Bytes())
+		fmt.Fprintf(&g.out, "return err\n}\n")
+	}
+}
+
+// structFields writes the Go struct fields for fields. The fields of every
+// MAP of a UNION are included, followed by the field selecting the MAP to
+// encode.
+func (g *generator) structFields(fields []*Field) {
+	for _, f := range fields {
+		switch {
+		case f.Kind == Union:
+			g.unions++
+			for _, m := range f.Maps {
+				g.structFields(m)
+			}
+			g.printf("Union%d int // member of UNION %d to encode, from 0\n", g.unions, g.unions)
+		case f.Name != "":
+			g.printf("%s %s // %s\n", goName(f.Name), goType(f), f.Decl)
+		}
+	}
+}
+
+// goType returns the Go type of the field.
+func goType(f *Field) string {
+	var sb strings.Builder
+	for _, d := range f.Dims {
+		fmt.Fprintf(&sb, "[%d]", d)
+	}
+	switch f.Kind {
+	case Integer:
+		if f.Unsigned {
+			sb.WriteString("u")
+		}
+		fmt.Fprintf(&sb, "int%d", 8*f.Len)
+	case Octaword:
+		sb.WriteString("vaxdata.VaxOctaword")
+	case Logical:
+		sb.WriteString("bool")
+	case FFloat:
+		if f.Complex {
+			sb.WriteString("complex64")
+		} else {
+			sb.WriteString("float32")
+		}
+	case DFloat, GFloat:
+		if f.Complex {
+			sb.WriteString("complex128")
+		} else {
+			sb.WriteString("float64")
+		}
+	case HFloat:
+		sb.WriteString("vaxdata.VaxHFloat")
+	case Decimal:
+		sb.WriteString("vaxdata.Decimal")
+	case Character:
+		sb.WriteString("string")
+	case Struct:
+		sb.WriteString(goName(f.Record.Name))
+	}
+	return sb.String()
+}
+
+// fields writes the code converting fields, starting at offset base.
+func (g *generator) fields(decode bool, fields []*Field, base int) {
+	for _, f := range fields {
+		switch {
+		case f.Kind == Union:
+			g.unions++
+			if decode {
+				for _, m := range f.Maps {
+					g.fields(decode, m, base)
+				}
+				break
+			}
+			g.printf("switch r.Union%d {\n", g.unions)
+			for i, m := range f.Maps {
+				g.printf("case %d:\n", i)
+				g.fields(decode, m, base)
+			}
+			g.printf("}\n")
+		case f.Name != "":
+			g.field(decode, f, base)
+		}
+		base += f.Size()
+	}
+}
+
+// field writes the code converting the named field f at offset base.
+func (g *generator) field(decode bool, f *Field, base int) {
+	name := goName(f.Name)
+	if len(f.Dims) == 0 {
+		g.elem(decode, f, "r."+name, strconv.Itoa(base), path{lit: name})
+		return
+	}
+
+	v, index := "r."+name, ""
+	p := path{expr: strconv.Quote(name + "[")}
+	for i, d := range f.Dims {
+		iv := fmt.Sprintf("i%d", i)
+		g.printf("for %s := range %s {\n", iv, v)
+		v += "[" + iv + "]"
+		switch i {
+		case 0:
+			index = iv
+			p.expr += " + strconv.Itoa(" + iv + ")"
+		case 1:
+			index = fmt.Sprintf("%s*%d + %s", index, d, iv)
+			p.expr += ` + "][" + strconv.Itoa(` + iv + ")"
+		default:
+			index = fmt.Sprintf("(%s)*%d + %s", index, d, iv)
+			p.expr += ` + "][" + strconv.Itoa(` + iv + ")"
+		}
+	}
+	p.lit = "]"
+
+	if len(f.Dims) > 1 {
+		index = "(" + index + ")"
+	}
+	if size := f.elemSize(); size > 1 {
+		index = fmt.Sprintf("%s*%d", index, size)
+	}
+	g.printf("o := %d + %s\n", base, index)
+	g.elem(decode, f, v, "o", p)
+	g.printf("%s\n", strings.Repeat("}", len(f.Dims)))
+}
+
+// path is the expression naming a value in errors: expr, if any, followed by
+// the literal text lit.
+type path struct {
+	expr string
+	lit  string
+}
+
+// with returns the path expression, with the literal text extended by
+// suffix.
+func (p path) with(suffix string) string {
+	lit := strconv.Quote(p.lit + suffix)
+	if p.expr == "" {
+		return lit
+	}
+	return p.expr + " + " + lit
+}
+
+// elem writes the code converting the single value v at offset o, which is
+// a constant or the variable o.
+func (g *generator) elem(decode bool, f *Field, v, o string, p path) {
+	size, bits := f.elemSize(), 8*f.Len
+
+	// off returns the expression for the offset o+delta
+	off := func(delta int) string {
+		if o != "o" {
+			n, _ := strconv.Atoi(o)
+			return strconv.Itoa(n + delta)
+		}
+		if delta == 0 {
+			return o
+		}
+		return fmt.Sprintf("o+%d", delta)
+	}
+	at := func(delta int) string { return "b[" + off(delta) + ":]" }
+	span := "b[" + off(0) + ":" + off(size) + "]"
+	offset := func(delta int) string {
+		if o == "o" {
+			return "int64(" + off(delta) + ")"
+		}
+		return off(delta)
+	}
+	fault := func(conv, suffix string, delta int) {
+		g.printf("if %s; e != nil && err == nil {\n", conv)
+		g.printf("err = &vaxdata.FieldError{Path: %s, Offset: %s, Err: e}\n}\n", p.with(suffix), offset(delta))
+		g.faults = true
+		g.strconv = g.strconv || o == "o"
+	}
+
+	switch f.Kind {
+	case Integer:
+		typ := fmt.Sprintf("int%d", bits)
+		if f.Unsigned {
+			typ = "u" + typ
+		}
+		switch {
+		case f.Len == 1 && decode:
+			g.printf("%s = %s(b[%s])\n", v, typ, off(0))
+		case f.Len == 1:
+			g.printf("b[%s] = byte(%s)\n", off(0), v)
+		case decode && f.Unsigned:
+			g.printf("%s = vaxdata.F.Uint%d(%s)\n", v, bits, at(0))
+		case decode:
+			g.printf("%s = %s(vaxdata.F.Uint%d(%s))\n", v, typ, bits, at(0))
+		case f.Unsigned:
+			g.printf("vaxdata.F.PutUint%d(%s, %s)\n", bits, at(0), v)
+		default:
+			g.printf("vaxdata.F.PutUint%d(%s, uint%d(%s))\n", bits, at(0), bits, v)
+		}
+	case Octaword:
+		if decode {
+			g.printf("%s = vaxdata.VaxOctaword{Lo: vaxdata.F.Uint64(%s), Hi: vaxdata.F.Uint64(%s)}\n", v, at(0), at(8))
+		} else {
+			g.printf("vaxdata.F.PutUint64(%s, %s.Lo)\nvaxdata.F.PutUint64(%s, %s.Hi)\n", at(0), v, at(8), v)
+		}
+	case Logical:
+		switch {
+		case decode:
+			g.printf("%s = b[%s]&1 != 0\n", v, off(0))
+		case f.Len == 1:
+			g.printf("if %s {\nb[%s] = 0xFF\n}\n", v, off(0))
+		default:
+			g.printf("if %s {\nvaxdata.F.PutUint%d(%s, ^uint%d(0))\n}\n", v, bits, at(0), bits)
+		}
+	case FFloat, DFloat, GFloat:
+		order, letter, bits := "F", "D", 64
+		switch f.Kind {
+		case FFloat:
+			letter, bits = "F", 32
+		case GFloat:
+			order, letter = "G", "G"
+		}
+		switch {
+		case decode && f.Complex:
+			fault(fmt.Sprintf("%s, e = vaxdata.Complex%dfromVax%sComplex(%s)", v, 2*bits, letter, span), "", 0)
+		case decode:
+			fault(fmt.Sprintf("%s, e = vaxdata.%s.Float%d(%s)", v, order, bits, at(0)), "", 0)
+		case f.Complex:
+			fault(fmt.Sprintf("e = vaxdata.%s.PutFloat%d(%s, real(%s))", order, bits, at(0), v), ".real", 0)
+			fault(fmt.Sprintf("e = vaxdata.%s.PutFloat%d(%s, imag(%s))", order, bits, at(size/2), v), ".imag", size/2)
+		default:
+			fault(fmt.Sprintf("e = vaxdata.%s.PutFloat%d(%s, %s)", order, bits, at(0), v), "", 0)
+		}
+	case HFloat:
+		if decode {
+			g.printf("copy(%s[:], %s)\n", v, span)
+		} else {
+			g.printf("copy(%s, %s[:])\n", span, v)
+		}
+	case Decimal:
+		if decode {
+			fault(fmt.Sprintf("%s, e = vaxdata.DecodePackedDecimal(%s, %d)", v, span, f.Scale), "", 0)
+			break
+		}
+		g.printf("if d, e := vaxdata.EncodePackedDecimal(%s, %d); e != nil {\n", v, f.Digits)
+		g.printf("if err == nil {\nerr = &vaxdata.FieldError{Path: %s, Offset: %s, Err: e}\n}\n", p.with(""), offset(0))
+		g.printf("} else {\ncopy(%s, d)\n}\n", span)
+		g.strconv = g.strconv || o == "o"
+	case Character:
+		if decode {
+			fault(fmt.Sprintf("%s, e = vaxdata.DecodeCharacter(%s, nil)", v, span), "", 0)
+		} else {
+			fault(fmt.Sprintf("e = vaxdata.EncodeCharacter(%s, %s, nil)", span, v), "", 0)
+		}
+	case Struct:
+		method := "encodeVAX"
+		if decode {
+			method = "decodeVAX"
+		}
+		g.printf("if fe := %s.%s(%s); fe != nil && err == nil {\n", v, method, at(0))
+		g.printf("fe.Path = %s + fe.Path\nfe.Offset += %s\nerr = fe\n}\n", p.with("."), offset(0))
+		g.strconv = g.strconv || o == "o"
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Kind is the VAX data type of a field in a record layout.
+type Kind int
+
+const (
+	Integer   Kind = iota // BYTE, WORD, LONGWORD or QUADWORD
+	Octaword              // OCTAWORD
+	Logical               // LOGICAL*1, *2, *4 or *8
+	FFloat                // F_Float
+	DFloat                // D_Float
+	GFloat                // G_Float
+	HFloat                // H_Float
+	Decimal               // packed decimal
+	Character             // fixed-length CHARACTER*n
+	Struct                // a nested record
+	Union                 // overlaid MAP's
+)
+
+// A Record is a record layout read from a declaration file.
+type Record struct {
+	Name   string // name in the declaration file
+	Decl   string // declaration, such as "STRUCTURE /SAMPLE/"
+	Fields []*Field
+}
+
+// A Field is one field of a Record. Filler fields have no name.
+type Field struct {
+	Name     string
+	Decl     string // declared type, such as "REAL*8"
+	Kind     Kind
+	Len      int        // length in bytes of other than a float, Struct or Union
+	Unsigned bool       // whether an Integer is unsigned
+	Complex  bool       // whether an F, D or G float is complex
+	Digits   int        // digits of a Decimal
+	Scale    int        // digits of a Decimal after the decimal point
+	Record   *Record    // record type of a Struct
+	Maps     [][]*Field // overlaid fields of a Union
+	Dims     []int      // array dimensions in Go order, outermost first
+}
+
+// Size returns the size in bytes of the record.
+func (r *Record) Size() int {
+	return fieldsSize(r.Fields)
+}
+
+func fieldsSize(fields []*Field) int {
+	size := 0
+	for _, f := range fields {
+		size += f.Size()
+	}
+	return size
+}
+
+// Size returns the size in bytes of the field, including every element of
+// an array.
+func (f *Field) Size() int {
+	size := f.elemSize()
+	for _, d := range f.Dims {
+		size *= d
+	}
+	return size
+}
+
+// elemSize returns the size in bytes of a single element of the field.
+func (f *Field) elemSize() int {
+	switch f.Kind {
+	case FFloat, DFloat, GFloat:
+		size := 8
+		if f.Kind == FFloat {
+			size = 4
+		}
+		if f.Complex {
+			size *= 2
+		}
+		return size
+	case Struct:
+		return f.Record.Size()
+	case Union:
+		size := 0
+		for _, m := range f.Maps {
+			size = max(size, fieldsSize(m))
+		}
+		return size
+	}
+	return f.Len
+}
+
+// goName converts a VAX name such as SAMPLE_RATE or IO$_READ to an exported
+// Go name such as SampleRate or IoRead. Names made up by the parsers use % as
+// a separator, as it cannot appear in a VAX name.
+func goName(name string) string {
+	var sb strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '$' || r == '%' }) {
+		for i, r := range strings.ToLower(part) {
+			if i == 0 {
+				r = unicode.ToUpper(r)
+			}
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
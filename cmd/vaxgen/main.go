@@ -0,0 +1,131 @@
+// Vaxgen generates Go types for VAX record layouts, with DecodeVAX and
+// EncodeVAX methods built on the vaxdata conversions. The generated code does
+// not use reflection.
+//
+// Usage:
+//
+//	vaxgen [flags] file...
+//
+// Files ending in .sdl hold VMS SDL declarations, while the others hold VAX
+// FORTRAN declarations.
+//
+// Each FORTRAN STRUCTURE becomes a Go struct type, as does each COMMON block,
+// whose variables are typed by the type statements, RECORD statements and
+// DIMENSION statements before it or by the IMPLICIT statements and default
+// implicit typing rules in effect. REAL*4,
+// REAL*8, DOUBLE PRECISION, BYTE, INTEGER*1, *2, *4 and *8, LOGICAL*1, *2, *4
+// and *8, CHARACTER*n and RECORD fields are supported, as are arrays, %FILL
+// and nested STRUCTURE's. INCLUDE statements are ignored; name included files
+// before the files that use their declarations.
+//
+// Each SDL AGGREGATE becomes a Go struct type, as does each sub-aggregate
+// STRUCTURE. BYTE, WORD, LONGWORD, QUADWORD and OCTAWORD, signed or
+// unsigned, F_FLOATING, D_FLOATING and G_FLOATING, real or COMPLEX,
+// H_FLOATING, DECIMAL, CHARACTER, BOOLEAN, ADDRESS and aggregate members are
+// supported, as are DIMENSION, FILL and integer CONSTANT's. A run of
+// BITFIELD's becomes one unsigned integer field named for the first of them.
+// Aggregates are laid out without alignment, as on the VAX.
+//
+// The fields of every MAP of a FORTRAN UNION, or every member of an SDL
+// UNION, are fields of the Go struct. DecodeVAX decodes all of them, while
+// EncodeVAX encodes the one chosen by the struct's UnionN field.
+//
+// The flags are:
+//
+//	-g
+//		REAL*8 and DOUBLE PRECISION are G_Float's, as compiled with
+//		/G_FLOATING, rather than D_Float's.
+//	-o file
+//		write the generated code to file rather than standard output.
+//	-package name
+//		the package of the generated code, by default main.
+//	-extend
+//		read FORTRAN statements up to column 132, as compiled with
+//		/EXTEND_SOURCE, rather than 72.
+//	-tags
+//		declare the structs with vax tags for vaxdata.Read and
+//		vaxdata.Write rather than generating methods. Records with
+//		a UNION cannot be declared this way, and DECIMAL fields are
+//		left as bytes.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	gfloat  = flag.Bool("g", false, "REAL*8 is G_Float rather than D_Float")
+	output  = flag.String("o", "", "write to `file` rather than standard output")
+	pkgName = flag.String("package", "main", "package `name` of the generated code")
+	extend  = flag.Bool("extend", false, "read statements up to column 132")
+	tags    = flag.Bool("tags", false, "declare structs with vax tags rather than methods")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: vaxgen [flags] file...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "vaxgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(files []string) error {
+	real8, width := DFloat, 72
+	if *gfloat {
+		real8 = GFloat
+	}
+	if *extend {
+		width = 132
+	}
+
+	fp, sp := newFortranParser(real8), newSDLParser()
+	var records []*Record
+	var sources []string
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(filepath.Ext(file), ".sdl") {
+			n := len(sp.records)
+			err = sp.parse(f, file)
+			records = append(records, sp.records[n:]...)
+		} else {
+			n := len(fp.records)
+			err = fp.parse(f, file, width)
+			records = append(records, fp.records[n:]...)
+		}
+		f.Close()
+		if err != nil {
+			return err
+		}
+		sources = append(sources, filepath.Base(file))
+	}
+
+	gen := generate
+	if *tags {
+		gen = generateTags
+	}
+	src, err := gen(*pkgName, sources, records)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0o666)
+}
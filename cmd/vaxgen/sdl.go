@@ -0,0 +1,556 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// sdlToken is a token of an SDL file. Names and keywords are in upper case
+// and numbers in any radix are in decimal.
+type sdlToken struct {
+	text string
+	line int
+}
+
+// sdlStatements splits SDL source into statements, dropping comments and the
+// text of LITERAL blocks. Errors are reported at lines of file.
+func sdlStatements(src, file string) ([][]sdlToken, error) {
+	var stmts [][]sdlToken
+	var stmt []sdlToken
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			i++
+		case strings.HasPrefix(src[i:], "/*"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == ';':
+			i++
+			if len(stmt) == 1 && stmt[0].text == "LITERAL" {
+				// skip to END_LITERAL
+				end := strings.Index(strings.ToUpper(src[i:]), "END_LITERAL")
+				if end < 0 {
+					return nil, fmt.Errorf("%s:%d: LITERAL without END_LITERAL", file, stmt[0].line)
+				}
+				line += strings.Count(src[i:i+end], "\n")
+				i += end
+				stmt = nil
+				continue
+			}
+			stmts = append(stmts, stmt)
+			stmt = nil
+		case c == '"':
+			end := strings.IndexByte(src[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%s:%d: unterminated string", file, line)
+			}
+			stmt = append(stmt, sdlToken{src[i : i+end+2], line})
+			i += end + 2
+		case c == '%' && i+1 < len(src) && strings.IndexByte("XxOoBbDd", src[i+1]) >= 0:
+			j := i + 2
+			for j < len(src) && isSDLNameByte(src[j]) {
+				j++
+			}
+			base := map[byte]int{'X': 16, 'O': 8, 'B': 2, 'D': 10}[src[i+1]&^0x20]
+			n, err := strconv.ParseInt(src[i+2:j], base, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: malformed number %q", file, line, src[i:j])
+			}
+			stmt = append(stmt, sdlToken{strconv.FormatInt(n, 10), line})
+			i = j
+		case isSDLNameByte(c):
+			j := i
+			for j < len(src) && isSDLNameByte(src[j]) {
+				j++
+			}
+			stmt = append(stmt, sdlToken{strings.ToUpper(src[i:j]), line})
+			i = j
+		default:
+			stmt = append(stmt, sdlToken{string(c), line})
+			i++
+		}
+	}
+	if len(stmt) > 0 {
+		return nil, fmt.Errorf("%s:%d: missing ;", file, stmt[0].line)
+	}
+	return stmts, nil
+}
+
+func isSDLNameByte(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '$'
+}
+
+// sdlParser builds record layouts from SDL AGGREGATE declarations.
+// Aggregates are laid out without alignment, as on the VAX.
+type sdlParser struct {
+	file string
+	line int
+
+	records    []*Record
+	aggregates map[string]*Record
+	constants  map[string]int
+	scopes     []*sdlScope
+}
+
+// sdlScope is an open AGGREGATE or sub-aggregate.
+type sdlScope struct {
+	name   string
+	record *Record // STRUCTURE being declared
+	union  *Field  // UNION being declared
+
+	run  *Field // current run of BITFIELD's
+	bits int    // bits in the run
+}
+
+func newSDLParser() *sdlParser {
+	return &sdlParser{
+		aggregates: make(map[string]*Record),
+		constants:  make(map[string]int),
+	}
+}
+
+func (p *sdlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", p.file, p.line, fmt.Sprintf(format, args...))
+}
+
+// parse reads the declarations in r, which is named file in errors.
+// Aggregates declared in earlier files may be used by later ones.
+func (p *sdlParser) parse(r io.Reader, file string) error {
+	p.file = file
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	stmts, err := sdlStatements(string(src), file)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range stmts {
+		if len(s) == 0 {
+			continue
+		}
+		p.line = s[0].line
+		if err := p.statement(s); err != nil {
+			return err
+		}
+	}
+	if len(p.scopes) > 0 {
+		return p.errorf("missing END %s", p.scopes[len(p.scopes)-1].name)
+	}
+	return nil
+}
+
+func (p *sdlParser) statement(s []sdlToken) error {
+	var top *sdlScope
+	if len(p.scopes) > 0 {
+		top = p.scopes[len(p.scopes)-1]
+	}
+
+	switch kw := s[0].text; {
+	case kw == "END":
+		if top == nil {
+			return p.errorf("END outside of an AGGREGATE")
+		}
+		if len(s) > 1 && s[1].text != top.name {
+			return p.errorf("END %s in %s", s[1].text, top.name)
+		}
+		if err := p.endRun(top); err != nil {
+			return err
+		}
+		p.scopes = p.scopes[:len(p.scopes)-1]
+		if top.record != nil && len(p.scopes) == 0 {
+			p.aggregates[top.name] = top.record
+		}
+		return nil
+	case top != nil:
+		return p.member(top, s)
+	case kw == "AGGREGATE":
+		return p.aggregate(s[1:])
+	case kw == "CONSTANT":
+		return p.constant(s[1:])
+	}
+	// MODULE, ITEM, ENTRY and the conditional directives have no layout
+	return nil
+}
+
+func (p *sdlParser) aggregate(s []sdlToken) error {
+	if len(s) < 2 || (s[1].text != "STRUCTURE" && s[1].text != "UNION") {
+		return p.errorf("AGGREGATE needs a name and STRUCTURE or UNION")
+	}
+	name := s[0].text
+	if _, ok := p.aggregates[name]; ok {
+		return p.errorf("AGGREGATE %s is already declared", name)
+	}
+	if _, err := p.options(s[2:], false); err != nil {
+		return err
+	}
+
+	r := &Record{Name: name, Decl: "AGGREGATE " + name}
+	sc := &sdlScope{name: name, record: r}
+	if s[1].text == "UNION" {
+		sc.union = &Field{Kind: Union, Decl: "UNION " + name}
+		r.Fields = []*Field{sc.union}
+	}
+	p.records = append(p.records, r)
+	p.scopes = append(p.scopes, sc)
+	return nil
+}
+
+// add adds a member to the open aggregate.
+func (p *sdlParser) add(sc *sdlScope, f *Field) {
+	if sc.union != nil {
+		sc.union.Maps = append(sc.union.Maps, []*Field{f})
+		return
+	}
+	sc.record.Fields = append(sc.record.Fields, f)
+}
+
+func (p *sdlParser) member(sc *sdlScope, s []sdlToken) error {
+	if len(s) < 2 {
+		return p.errorf("member %s needs a type", s[0].text)
+	}
+	name := s[0].text
+
+	if kw := s[1].text; kw == "STRUCTURE" || kw == "UNION" {
+		if err := p.endRun(sc); err != nil {
+			return err
+		}
+		opts, err := p.options(s[2:], false)
+		if err != nil {
+			return err
+		}
+
+		sub := &sdlScope{name: name}
+		if kw == "UNION" {
+			if opts.dims != nil {
+				return p.errorf("UNION %s cannot have a DIMENSION", name)
+			}
+			sub.union = &Field{Kind: Union, Decl: "UNION " + name}
+			p.add(sc, sub.union)
+		} else {
+			r := &Record{
+				Name: "%" + p.recordName(sc) + "_" + name,
+				Decl: fmt.Sprintf("the STRUCTURE %s in AGGREGATE %s", name, p.scopes[0].name),
+			}
+			p.records = append(p.records, r)
+			sub.record = r
+			f := &Field{Name: name, Kind: Struct, Record: r, Decl: "STRUCTURE", Dims: opts.dims}
+			if opts.fill {
+				f.Name = ""
+			}
+			p.add(sc, f)
+		}
+		p.scopes = append(p.scopes, sub)
+		return nil
+	}
+
+	t, rest, err := p.dataType(s[1:])
+	if err != nil {
+		return err
+	}
+	opts, err := p.options(rest, t.Decl == "BITFIELD")
+	if err != nil {
+		return err
+	}
+
+	if t.Decl == "BITFIELD" {
+		if opts.dims != nil {
+			return p.errorf("BITFIELD %s cannot have a DIMENSION", name)
+		}
+		if sc.run == nil {
+			sc.run = &Field{Name: name, Kind: Integer, Unsigned: true, Decl: "BITFIELD " + name}
+			p.add(sc, sc.run)
+		} else {
+			sc.run.Decl += ", " + name
+		}
+		sc.bits += opts.length
+		if sc.union != nil {
+			return p.endRun(sc)
+		}
+		return nil
+	}
+
+	if err := p.endRun(sc); err != nil {
+		return err
+	}
+	f := t
+	f.Name, f.Dims = name, opts.dims
+	if opts.fill {
+		f.Name = ""
+	}
+	p.add(sc, &f)
+	return nil
+}
+
+// recordName returns the name of the record of the innermost STRUCTURE
+// enclosing sc.
+func (p *sdlParser) recordName(sc *sdlScope) string {
+	for i := len(p.scopes) - 1; i >= 0; i-- {
+		if r := p.scopes[i].record; r != nil {
+			return strings.TrimPrefix(r.Name, "%")
+		}
+	}
+	return sc.name
+}
+
+// endRun ends a run of BITFIELD's, which is stored as an unsigned integer
+// holding every bit of the run.
+func (p *sdlParser) endRun(sc *sdlScope) error {
+	if sc.run == nil {
+		return nil
+	}
+	f := sc.run
+	f.Len = (sc.bits + 7) / 8
+	sc.run, sc.bits = nil, 0
+	if f.Len != 1 && f.Len != 2 && f.Len != 4 && f.Len != 8 {
+		return p.errorf("%s is %d bytes, which is not an integer size", f.Decl, f.Len)
+	}
+	return nil
+}
+
+// dataType parses an SDL data type, returning a template Field and the
+// tokens following the type.
+func (p *sdlParser) dataType(s []sdlToken) (Field, []sdlToken, error) {
+	kw, rest := s[0].text, s[1:]
+	unsigned := len(rest) > 0 && rest[0].text == "UNSIGNED"
+	if len(rest) > 0 && (rest[0].text == "SIGNED" || rest[0].text == "UNSIGNED") && kw != "BITFIELD" {
+		rest = rest[1:]
+	}
+	complex := len(rest) > 0 && rest[0].text == "COMPLEX"
+	if complex {
+		rest = rest[1:]
+	}
+
+	decl := kw
+	if unsigned {
+		decl += " UNSIGNED"
+	}
+	if complex {
+		decl += " COMPLEX"
+	}
+
+	if r, ok := p.aggregates[kw]; ok {
+		return Field{Kind: Struct, Record: r, Decl: "AGGREGATE " + kw}, rest, nil
+	}
+
+	sizes := map[string]int{"BYTE": 1, "WORD": 2, "LONGWORD": 4, "QUADWORD": 8}
+	switch {
+	case sizes[kw] > 0 && !complex:
+		return Field{Kind: Integer, Len: sizes[kw], Unsigned: unsigned, Decl: decl}, rest, nil
+	case kw == "OCTAWORD" && !complex:
+		return Field{Kind: Octaword, Len: 16, Decl: decl}, rest, nil
+	case kw == "F_FLOATING":
+		return Field{Kind: FFloat, Complex: complex, Decl: decl}, rest, nil
+	case kw == "D_FLOATING":
+		return Field{Kind: DFloat, Complex: complex, Decl: decl}, rest, nil
+	case kw == "G_FLOATING":
+		return Field{Kind: GFloat, Complex: complex, Decl: decl}, rest, nil
+	case kw == "H_FLOATING" && !complex:
+		return Field{Kind: HFloat, Len: 16, Decl: decl}, rest, nil
+	case kw == "BOOLEAN":
+		return Field{Kind: Logical, Len: 1, Decl: decl}, rest, nil
+	case kw == "ADDRESS" || kw == "POINTER" || kw == "POINTER_LONG":
+		if len(rest) > 0 && rest[0].text == "(" {
+			end := 0
+			for end < len(rest) && rest[end].text != ")" {
+				end++
+			}
+			rest = rest[min(end+1, len(rest)):]
+		}
+		return Field{Kind: Integer, Len: 4, Unsigned: true, Decl: kw}, rest, nil
+	case kw == "DECIMAL":
+		if len(rest) < 6 || rest[0].text != "PRECISION" || rest[1].text != "(" || rest[3].text != "," || rest[5].text != ")" {
+			return Field{}, nil, p.errorf("DECIMAL needs PRECISION (p,q)")
+		}
+		digits, err := p.value(rest[2].text)
+		if err != nil {
+			return Field{}, nil, err
+		}
+		scale, err := p.value(rest[4].text)
+		if err != nil {
+			return Field{}, nil, err
+		}
+		if digits < 1 || digits > 31 {
+			return Field{}, nil, p.errorf("invalid DECIMAL precision %d", digits)
+		}
+		return Field{
+			Kind:   Decimal,
+			Len:    digits/2 + 1,
+			Digits: digits,
+			Scale:  scale,
+			Decl:   fmt.Sprintf("DECIMAL PRECISION (%d,%d)", digits, scale),
+		}, rest[6:], nil
+	case kw == "CHARACTER":
+		n := 1
+		if len(rest) > 1 && rest[0].text == "LENGTH" {
+			if rest[1].text == "*" {
+				return Field{}, nil, p.errorf("CHARACTER LENGTH * has no layout")
+			}
+			var err error
+			if n, err = p.value(rest[1].text); err != nil {
+				return Field{}, nil, err
+			}
+			if n < 1 {
+				return Field{}, nil, p.errorf("invalid CHARACTER LENGTH %d", n)
+			}
+			rest = rest[2:]
+		}
+		if len(rest) > 0 && rest[0].text == "VARYING" {
+			return Field{}, nil, p.errorf("CHARACTER VARYING is not supported")
+		}
+		return Field{Kind: Character, Len: n, Decl: fmt.Sprintf("CHARACTER LENGTH %d", n)}, rest, nil
+	case kw == "BITFIELD":
+		return Field{Decl: kw}, rest, nil
+	}
+	return Field{}, nil, p.errorf("%s is not supported", decl)
+}
+
+// sdlOptions are the options of a member that affect its layout.
+type sdlOptions struct {
+	dims   []int
+	fill   bool
+	length int // of a BITFIELD
+}
+
+// options parses the options following a member's type or an aggregate.
+// Options affecting only the names or alignment of declarations in other
+// languages are ignored.
+func (p *sdlParser) options(s []sdlToken, bitfield bool) (sdlOptions, error) {
+	opts := sdlOptions{length: 1}
+	for len(s) > 0 {
+		kw := s[0].text
+		s = s[1:]
+		switch kw {
+		case "DIMENSION":
+			lo, hi := 1, 0
+			n, rest, err := p.bound(s)
+			if err != nil {
+				return opts, err
+			}
+			hi, s = n, rest
+			if len(s) > 0 && s[0].text == ":" {
+				if hi, s, err = p.bound(s[1:]); err != nil {
+					return opts, err
+				}
+				lo = n
+			}
+			if hi < lo {
+				return opts, p.errorf("invalid DIMENSION %d:%d", lo, hi)
+			}
+			opts.dims = []int{hi - lo + 1}
+		case "FILL":
+			opts.fill = true
+		case "LENGTH":
+			if !bitfield || len(s) == 0 {
+				return opts, p.errorf("unexpected LENGTH")
+			}
+			n, err := p.value(s[0].text)
+			if err != nil {
+				return opts, err
+			}
+			opts.length, s = n, s[1:]
+		case "PREFIX", "TAG", "BASED", "ORIGIN", "TYPENAME", "MARKER":
+			if len(s) == 0 {
+				return opts, p.errorf("%s needs a value", kw)
+			}
+			s = s[1:]
+		case "BASEALIGN":
+			if len(s) > 0 && s[0].text == "(" {
+				for len(s) > 0 && s[0].text != ")" {
+					s = s[1:]
+				}
+			}
+			if len(s) > 0 {
+				s = s[1:]
+			}
+		case "ALIGN", "NOALIGN", "COMMON", "GLOBAL", "TYPEDEF", "MASK", "SIGNED", "UNSIGNED":
+		default:
+			return opts, p.errorf("unknown option %s", kw)
+		}
+	}
+	return opts, nil
+}
+
+// bound parses a DIMENSION bound, which may be negative.
+func (p *sdlParser) bound(s []sdlToken) (int, []sdlToken, error) {
+	neg := len(s) > 0 && s[0].text == "-"
+	if neg {
+		s = s[1:]
+	}
+	if len(s) == 0 {
+		return 0, nil, p.errorf("missing DIMENSION")
+	}
+	n, err := p.value(s[0].text)
+	if neg {
+		n = -n
+	}
+	return n, s[1:], err
+}
+
+// value returns the value of an integer or CONSTANT.
+func (p *sdlParser) value(s string) (int, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	if n, ok := p.constants[s]; ok {
+		return n, nil
+	}
+	return 0, p.errorf("%s is not an integer constant", s)
+}
+
+// constant parses the integer constants of a CONSTANT declaration, either a
+// list of "name EQUALS value" or "(name, ...) EQUALS value INCREMENT step".
+// Constants with other values are ignored.
+func (p *sdlParser) constant(s []sdlToken) error {
+	var names []string
+	if len(s) > 0 && s[0].text == "(" {
+		s = s[1:]
+		for len(s) > 0 && s[0].text != ")" {
+			if s[0].text != "," {
+				names = append(names, s[0].text)
+			}
+			s = s[1:]
+		}
+		if len(s) < 3 || s[1].text != "EQUALS" {
+			return nil
+		}
+		start, err := p.value(s[2].text)
+		if err != nil {
+			return nil
+		}
+		step := 1
+		if len(s) > 4 && s[3].text == "INCREMENT" {
+			if step, err = p.value(s[4].text); err != nil {
+				return nil
+			}
+		}
+		for i, name := range names {
+			p.constants[name] = start + i*step
+		}
+		return nil
+	}
+
+	for len(s) >= 3 {
+		if s[1].text == "EQUALS" {
+			if n, err := p.value(s[2].text); err == nil {
+				p.constants[s[0].text] = n
+			}
+		}
+		for len(s) > 0 && s[0].text != "," {
+			s = s[1:]
+		}
+		if len(s) > 0 {
+			s = s[1:]
+		}
+	}
+	return nil
+}
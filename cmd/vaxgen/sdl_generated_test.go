@@ -0,0 +1,251 @@
+// Code generated by vaxgen from sample.sdl; DO NOT EDIT.
+
+package main
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/sixlettervariables/vaxdata"
+)
+
+// Leg is the layout of AGGREGATE LEG.
+type Leg struct {
+	Price float64 // D_FLOATING
+	Qty   uint32  // LONGWORD UNSIGNED
+}
+
+// VAXSize returns the size in bytes of the VAX representation of a Leg.
+func (*Leg) VAXSize() int { return 12 }
+
+// DecodeVAX decodes r from the VAX representation at the start of b. Every
+// field is decoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *Leg) DecodeVAX(b []byte) error {
+	if len(b) < 12 {
+		return io.ErrShortBuffer
+	}
+	if err := r.decodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// EncodeVAX stores the VAX representation of r at the start of b. Every
+// field is encoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *Leg) EncodeVAX(b []byte) error {
+	if len(b) < 12 {
+		return io.ErrShortBuffer
+	}
+	clear(b[:12])
+	if err := r.encodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Leg) decodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	if r.Price, e = vaxdata.F.Float64(b[0:]); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Price", Offset: 0, Err: e}
+	}
+	r.Qty = vaxdata.F.Uint32(b[8:])
+	return err
+}
+
+func (r *Leg) encodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	if e = vaxdata.F.PutFloat64(b[0:], r.Price); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Price", Offset: 0, Err: e}
+	}
+	vaxdata.F.PutUint32(b[8:], r.Qty)
+	return err
+}
+
+// Trade is the layout of AGGREGATE TRADE.
+type Trade struct {
+	Id        int64               // QUADWORD
+	Side      uint8               // BYTE UNSIGNED
+	Active    bool                // BOOLEAN
+	Amount    vaxdata.Decimal     // DECIMAL PRECISION (9,2)
+	Symbol    string              // CHARACTER LENGTH 6
+	Rate      float64             // G_FLOATING
+	Impedance complex64           // F_FLOATING COMPLEX
+	Total     vaxdata.VaxHFloat   // H_FLOATING
+	Sequence  vaxdata.VaxOctaword // OCTAWORD UNSIGNED
+	Legs      [2]Leg              // AGGREGATE LEG
+	Flags     TradeFlags          // STRUCTURE
+	Code      int32               // LONGWORD
+	Tags      [4]int8             // BYTE
+	Union1    int                 // member of UNION 1 to encode, from 0
+}
+
+// VAXSize returns the size in bytes of the VAX representation of a Trade.
+func (*Trade) VAXSize() int { return 100 }
+
+// DecodeVAX decodes r from the VAX representation at the start of b. Every
+// field is decoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *Trade) DecodeVAX(b []byte) error {
+	if len(b) < 100 {
+		return io.ErrShortBuffer
+	}
+	if err := r.decodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// EncodeVAX stores the VAX representation of r at the start of b. Every
+// field is encoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *Trade) EncodeVAX(b []byte) error {
+	if len(b) < 100 {
+		return io.ErrShortBuffer
+	}
+	clear(b[:100])
+	if err := r.encodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Trade) decodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	r.Id = int64(vaxdata.F.Uint64(b[0:]))
+	r.Side = uint8(b[8])
+	r.Active = b[9]&1 != 0
+	if r.Amount, e = vaxdata.DecodePackedDecimal(b[12:17], 2); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Amount", Offset: 12, Err: e}
+	}
+	if r.Symbol, e = vaxdata.DecodeCharacter(b[17:23], nil); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Symbol", Offset: 17, Err: e}
+	}
+	if r.Rate, e = vaxdata.G.Float64(b[23:]); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Rate", Offset: 23, Err: e}
+	}
+	if r.Impedance, e = vaxdata.Complex64fromVaxFComplex(b[31:39]); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Impedance", Offset: 31, Err: e}
+	}
+	copy(r.Total[:], b[39:55])
+	r.Sequence = vaxdata.VaxOctaword{Lo: vaxdata.F.Uint64(b[55:]), Hi: vaxdata.F.Uint64(b[63:])}
+	for i0 := range r.Legs {
+		o := 71 + i0*12
+		if fe := r.Legs[i0].decodeVAX(b[o:]); fe != nil && err == nil {
+			fe.Path = "Legs[" + strconv.Itoa(i0) + "]." + fe.Path
+			fe.Offset += int64(o)
+			err = fe
+		}
+	}
+	if fe := r.Flags.decodeVAX(b[95:]); fe != nil && err == nil {
+		fe.Path = "Flags." + fe.Path
+		fe.Offset += 95
+		err = fe
+	}
+	r.Code = int32(vaxdata.F.Uint32(b[96:]))
+	for i0 := range r.Tags {
+		o := 96 + i0
+		r.Tags[i0] = int8(b[o])
+	}
+	return err
+}
+
+func (r *Trade) encodeVAX(b []byte) (err *vaxdata.FieldError) {
+	var e error
+	vaxdata.F.PutUint64(b[0:], uint64(r.Id))
+	b[8] = byte(r.Side)
+	if r.Active {
+		b[9] = 0xFF
+	}
+	if d, e := vaxdata.EncodePackedDecimal(r.Amount, 9); e != nil {
+		if err == nil {
+			err = &vaxdata.FieldError{Path: "Amount", Offset: 12, Err: e}
+		}
+	} else {
+		copy(b[12:17], d)
+	}
+	if e = vaxdata.EncodeCharacter(b[17:23], r.Symbol, nil); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Symbol", Offset: 17, Err: e}
+	}
+	if e = vaxdata.G.PutFloat64(b[23:], r.Rate); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Rate", Offset: 23, Err: e}
+	}
+	if e = vaxdata.F.PutFloat32(b[31:], real(r.Impedance)); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Impedance.real", Offset: 31, Err: e}
+	}
+	if e = vaxdata.F.PutFloat32(b[35:], imag(r.Impedance)); e != nil && err == nil {
+		err = &vaxdata.FieldError{Path: "Impedance.imag", Offset: 35, Err: e}
+	}
+	copy(b[39:55], r.Total[:])
+	vaxdata.F.PutUint64(b[55:], r.Sequence.Lo)
+	vaxdata.F.PutUint64(b[63:], r.Sequence.Hi)
+	for i0 := range r.Legs {
+		o := 71 + i0*12
+		if fe := r.Legs[i0].encodeVAX(b[o:]); fe != nil && err == nil {
+			fe.Path = "Legs[" + strconv.Itoa(i0) + "]." + fe.Path
+			fe.Offset += int64(o)
+			err = fe
+		}
+	}
+	if fe := r.Flags.encodeVAX(b[95:]); fe != nil && err == nil {
+		fe.Path = "Flags." + fe.Path
+		fe.Offset += 95
+		err = fe
+	}
+	switch r.Union1 {
+	case 0:
+		vaxdata.F.PutUint32(b[96:], uint32(r.Code))
+	case 1:
+		for i0 := range r.Tags {
+			o := 96 + i0
+			b[o] = byte(r.Tags[i0])
+		}
+	}
+	return err
+}
+
+// TradeFlags is the layout of the STRUCTURE FLAGS in AGGREGATE TRADE.
+type TradeFlags struct {
+	Open uint8 // BITFIELD OPEN, SETTLED, KIND
+}
+
+// VAXSize returns the size in bytes of the VAX representation of a TradeFlags.
+func (*TradeFlags) VAXSize() int { return 1 }
+
+// DecodeVAX decodes r from the VAX representation at the start of b. Every
+// field is decoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *TradeFlags) DecodeVAX(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrShortBuffer
+	}
+	if err := r.decodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// EncodeVAX stores the VAX representation of r at the start of b. Every
+// field is encoded, with conversion faults fixed up, and the first fault is
+// returned as a *vaxdata.FieldError.
+func (r *TradeFlags) EncodeVAX(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrShortBuffer
+	}
+	clear(b[:1])
+	if err := r.encodeVAX(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *TradeFlags) decodeVAX(b []byte) (err *vaxdata.FieldError) {
+	r.Open = uint8(b[0])
+	return err
+}
+
+func (r *TradeFlags) encodeVAX(b []byte) (err *vaxdata.FieldError) {
+	b[0] = byte(r.Open)
+	return err
+}
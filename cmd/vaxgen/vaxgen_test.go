@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sixlettervariables/vaxdata"
+)
+
+// fortran_generated_test.go and sdl_generated_test.go are the output of
+//
+//	vaxgen testdata/sample.for > fortran_generated_test.go
+//	vaxgen testdata/sample.sdl > sdl_generated_test.go
+//
+// and testdata/implicit.golden, which is compared but not compiled, of
+//
+//	vaxgen testdata/implicit.for > testdata/implicit.golden
+
+// parseFile parses a file of declarations as vaxgen does, without -g.
+func parseFile(t *testing.T, file string) []*Record {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(file, ".sdl") {
+		p := newSDLParser()
+		if err := p.parse(f, file); err != nil {
+			t.Fatalf("parse(%s) raised unexpected error: %q", file, err)
+		}
+		return p.records
+	}
+	p := newFortranParser(DFloat)
+	if err := p.parse(f, file, 72); err != nil {
+		t.Fatalf("parse(%s) raised unexpected error: %q", file, err)
+	}
+	return p.records
+}
+
+func TestGenerate(t *testing.T) {
+	var tests = []struct {
+		source, golden string
+	}{
+		{"sample.for", "fortran_generated_test.go"},
+		{"sample.sdl", "sdl_generated_test.go"},
+		{"implicit.for", "testdata/implicit.golden"},
+	}
+
+	for _, tt := range tests {
+		records := parseFile(t, "testdata/"+tt.source)
+		got, err := generate("main", []string{tt.source}, records)
+		if err != nil {
+			t.Fatalf("generate(%s) raised unexpected error: %q", tt.source, err)
+		}
+
+		want, err := os.ReadFile(tt.golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("generated code differs from %s; regenerate it with vaxgen", tt.golden)
+		}
+	}
+}
+
+func TestGeneratedCode(t *testing.T) {
+	in := Sample{
+		Id:      -2,
+		Valid:   true,
+		Rate:    math.Pi,
+		Station: "PALOMAR",
+		Origin:  Point{1, 3.5},
+		Track:   [2]Point{{-1, 0}, {0.5, 2}},
+		Values:  [2][3]float32{{1, 2, 3}, {4, 5, 6}},
+		Code:    0x04030201,
+		Sensor:  SampleSensor{"SEIS", 12},
+	}
+
+	b := make([]byte, in.VAXSize())
+	if err := in.EncodeVAX(b); err != nil {
+		t.Fatalf("EncodeVAX raised unexpected error: %q", err)
+	}
+
+	want := map[int][]byte{
+		0:  {0xFE, 0xFF, 0xFF, 0xFF},                         // ID
+		4:  {0xFF, 0xFF, 0x00, 0x00},                         // VALID, %FILL
+		8:  {0x68, 0xC0, 0xA2, 0x21, 0x0F, 0xDA, 0x41, 0x49}, // RATE
+		16: []byte("PALOMAR "),                               // STATION
+		24: {0x00, 0x00, 0x40, 0x80},                         // ORIGIN.X
+		52: {0x00, 0x00, 0x41, 0x00},                         // VALUES(2,1)
+		72: {0x01, 0x02, 0x03, 0x04},                         // CODE
+		76: []byte("SEIS\x0C\x00"),                           // SENSOR
+	}
+	for offset, w := range want {
+		if got := b[offset : offset+len(w)]; !bytes.Equal(got, w) {
+			t.Errorf("EncodeVAX stored %X at offset %d, want %X", got, offset, w)
+		}
+	}
+
+	var out Sample
+	if err := out.DecodeVAX(b); err != nil {
+		t.Fatalf("DecodeVAX raised unexpected error: %q", err)
+	}
+	in.Flags = [4]int8{1, 2, 3, 4}
+	if out != in {
+		t.Errorf("DecodeVAX(%X) == %+v, want %+v", b, out, in)
+	}
+
+	// Encode the second MAP of the UNION
+	out.Union1, out.Flags = 1, [4]int8{-1, 0, 0, 0}
+	if err := out.EncodeVAX(b); err != nil {
+		t.Fatalf("EncodeVAX raised unexpected error: %q", err)
+	}
+	if got := b[72:76]; !bytes.Equal(got, []byte{0xFF, 0, 0, 0}) {
+		t.Errorf("EncodeVAX of MAP 1 stored %X, want FF000000", got)
+	}
+
+	if err := out.DecodeVAX(b[:81]); err != io.ErrShortBuffer {
+		t.Errorf("DecodeVAX of a short buffer raised %v, want io.ErrShortBuffer", err)
+	}
+}
+
+func TestGeneratedCodeFault(t *testing.T) {
+	var c StatsCommon
+	b := make([]byte, c.VAXSize())
+
+	// Mark LAST.TRACK(2).Y as a reserved operand
+	copy(b[10+32+8+4:], []byte{0x00, 0x00, 0x80, 0x00})
+	err := c.DecodeVAX(b)
+	var fe *vaxdata.FieldError
+	if !errors.As(err, &fe) || fe.Path != "Last.Track[1].Y" || fe.Offset != 54 {
+		t.Errorf("DecodeVAX raised %v, want a fault in Last.Track[1].Y at offset 54", err)
+	}
+
+	c.Last.Values[1][2] = float32(math.Inf(1))
+	err = c.EncodeVAX(b)
+	if !errors.As(err, &fe) || fe.Path != "Last.Values[1][2]" || fe.Offset != 10+48+20 {
+		t.Errorf("EncodeVAX raised %v, want a fault in Last.Values[1][2] at offset 78", err)
+	}
+}
+
+func TestReadStatements(t *testing.T) {
+	src := "C comment\n" +
+		"* comment\n" +
+		"      integer*2 a,   ! trailing comment\n" +
+		"     1          b\n" +
+		"\tREAL*4 C\n" +
+		"\t1, D\n" +
+		"      CHARACTER*4 E /'a b!'/" + strings.Repeat(" ", 50) + "IGNORED\n" +
+		"D     DEBUG LINE\n"
+
+	stmts, err := readStatements(strings.NewReader(src), "x.for", 72)
+	if err != nil {
+		t.Fatalf("readStatements raised unexpected error: %q", err)
+	}
+	want := []statement{
+		{"INTEGER*2A,B", 3},
+		{"REAL*4C,D", 5},
+		{"CHARACTER*4E/'a b!'/", 7},
+	}
+	if len(stmts) != len(want) {
+		t.Fatalf("readStatements == %v, want %v", stmts, want)
+	}
+	for i := range want {
+		if stmts[i] != want[i] {
+			t.Errorf("statement %d == %v, want %v", i, stmts[i], want[i])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	var tests = []struct {
+		src, err string
+	}{
+		{"      STRUCTURE /A/\n      INTEGER*3 X\n      END STRUCTURE\n", "x.for:2: INTEGER*3 is not supported"},
+		{"      STRUCTURE /A/\n      COMPLEX Z\n      END STRUCTURE\n", "x.for:2: COMPLEX is not supported"},
+		{"      STRUCTURE /A/\n      RECORD /B/ X\n      END STRUCTURE\n", "x.for:2: unknown STRUCTURE /B/"},
+		{"      STRUCTURE /A/\n      RECORD /A/ X\n      END STRUCTURE\n", "x.for:2: unknown STRUCTURE /A/"},
+		{"      STRUCTURE /A/\n      X = 1\n      END STRUCTURE\n", "x.for:2: unexpected statement in STRUCTURE"},
+		{"      STRUCTURE /A/\n      UNION\n      INTEGER X\n", "x.for:3: unexpected statement in UNION"},
+		{"      STRUCTURE /A/\n      INTEGER X\n", "x.for:2: missing END STRUCTURE"},
+		{"      END MAP\n", "x.for:1: END MAP without MAP"},
+		{"      STRUCTURE\n      END STRUCTURE\n", "x.for:1: top level STRUCTURE needs a name"},
+		{"     1INTEGER X\n", "x.for:1: continuation line without a statement"},
+		{"      COMPLEX Z\n      COMMON /C/ Z\n", "x.for:1: COMPLEX is not supported"},
+		{"      STRUCTURE /A/\n      REAL X(N)\n      END STRUCTURE\n", `x.for:2: "N" is not an integer constant`},
+		{"      IMPLICIT NONE\n      INTEGER N\n      COMMON /C/ N, X\n", "x.for:3: X is not declared, and IMPLICIT NONE is in effect"},
+		{"      IMPLICIT COMPLEX (Z)\n      COMMON /C/ Z\n", "x.for:1: COMPLEX is not supported"},
+		{"      IMPLICIT REAL*8 (H-A)\n", `x.for:1: invalid IMPLICIT letter range "H-A"`},
+		{"      IMPLICIT RECORD /A/ (R)\n", "x.for:1: IMPLICIT RECORD/A/(R) is not supported"},
+		{"      COMMON /A/ %FILL\n", "x.for:1: %FILL is not allowed in COMMON"},
+		{"      DIMENSION %FILL(3)\n", "x.for:1: %FILL is not allowed in DIMENSION"},
+	}
+
+	for _, tt := range tests {
+		p := newFortranParser(DFloat)
+		err := p.parse(strings.NewReader(tt.src), "x.for", 72)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("parse(%q) raised %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
+
+func TestCommonLayout(t *testing.T) {
+	src := "      PARAMETER (N = 4)\n" +
+		"      REAL*8 TIME\n" +
+		"      DIMENSION LEVEL(0:N)\n" +
+		"      COMMON /BLK/ TIME, LEVEL, X(2,3)\n" +
+		"      COMMON // K\n" +
+		"      END\n"
+
+	p := newFortranParser(GFloat)
+	if err := p.parse(strings.NewReader(src), "x.for", 72); err != nil {
+		t.Fatalf("parse raised unexpected error: %q", err)
+	}
+	if len(p.records) != 2 {
+		t.Fatalf("parse found %d records, want 2", len(p.records))
+	}
+
+	blk := p.records[0]
+	if goName(blk.Name) != "BlkCommon" || blk.Size() != 8+5*4+6*4 {
+		t.Errorf("COMMON /BLK/ is %s of %d bytes, want BlkCommon of 52 bytes", goName(blk.Name), blk.Size())
+	}
+	var types []string
+	for _, f := range blk.Fields {
+		types = append(types, goType(f))
+	}
+	if got := strings.Join(types, " "); got != "float64 [5]int32 [3][2]float32" {
+		t.Errorf("COMMON /BLK/ types are %s, want float64 [5]int32 [3][2]float32", got)
+	}
+	if blk.Fields[0].Kind != GFloat {
+		t.Errorf("REAL*8 under -g is %v, want GFloat", blk.Fields[0].Kind)
+	}
+	if goName(p.records[1].Name) != "BlankCommon" {
+		t.Errorf("blank COMMON is named %s, want BlankCommon", goName(p.records[1].Name))
+	}
+}
+
+func TestSDLGeneratedCode(t *testing.T) {
+	in := Trade{
+		Id:        -3,
+		Side:      2,
+		Active:    true,
+		Amount:    vaxdata.Decimal{Value: big.NewInt(-123456), Scale: 2},
+		Symbol:    "DEC",
+		Rate:      math.Pi,
+		Impedance: complex(1, -1),
+		Total:     vaxdata.VaxHFloat{12: 0x01, 13: 0x40}, // 1.0
+		Sequence:  vaxdata.VaxOctaword{Lo: 1, Hi: 2},
+		Legs:      [2]Leg{{Price: 1, Qty: 100}, {Price: -1, Qty: 200}},
+		Flags:     TradeFlags{Open: 0x05},
+		Code:      0x44434241,
+	}
+
+	b := make([]byte, in.VAXSize())
+	if len(b) != 100 {
+		t.Fatalf("Trade.VAXSize() == %d, want 100", len(b))
+	}
+	if err := in.EncodeVAX(b); err != nil {
+		t.Fatalf("EncodeVAX raised unexpected error: %q", err)
+	}
+
+	want := map[int][]byte{
+		0:  {0xFD, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, // ID
+		8:  {0x02, 0xFF, 0x00, 0x00},                         // SIDE, ACTIVE, SPARE
+		12: {0x00, 0x01, 0x23, 0x45, 0x6D},                   // AMOUNT
+		17: []byte("DEC   "),                                 // SYMBOL
+		23: {0x2D, 0x18, 0x54, 0x44, 0x21, 0xFB, 0x40, 0x29}, // RATE
+		31: {0x00, 0x00, 0x40, 0x80, 0x00, 0x00, 0xC0, 0x80}, // IMPEDANCE
+		55: {0x01, 0, 0, 0, 0, 0, 0, 0, 0x02},                // SEQUENCE
+		79: {0x64, 0x00, 0x00, 0x00},                         // LEGS(0).QTY
+		95: {0x05, 0x41, 0x42, 0x43, 0x44},                   // FLAGS, CODE
+	}
+	for offset, w := range want {
+		if got := b[offset : offset+len(w)]; !bytes.Equal(got, w) {
+			t.Errorf("EncodeVAX stored %X at offset %d, want %X", got, offset, w)
+		}
+	}
+
+	var out Trade
+	if err := out.DecodeVAX(b); err != nil {
+		t.Fatalf("DecodeVAX raised unexpected error: %q", err)
+	}
+	if out.Amount.String() != "-1234.56" || out.Tags != [4]int8{0x41, 0x42, 0x43, 0x44} {
+		t.Errorf("DecodeVAX(%X) == %+v", b, out)
+	}
+	out.Amount, in.Amount, out.Tags = vaxdata.Decimal{}, vaxdata.Decimal{}, [4]int8{}
+	if out != in {
+		t.Errorf("DecodeVAX(%X) == %+v, want %+v", b, out, in)
+	}
+
+	in.Amount = vaxdata.Decimal{Value: big.NewInt(1e9)}
+	err := in.EncodeVAX(b)
+	var fe *vaxdata.FieldError
+	if !errors.As(err, &fe) || fe.Path != "Amount" || fe.Offset != 12 {
+		t.Errorf("EncodeVAX of a 10 digit Amount raised %v, want a fault in Amount at offset 12", err)
+	}
+}
+
+func TestSDLTags(t *testing.T) {
+	src := `AGGREGATE ACCOUNT STRUCTURE;
+    BALANCE DECIMAL PRECISION (7,2);
+    NAME CHARACTER LENGTH 10;
+    SPARE WORD FILL;
+    RATES F_FLOATING DIMENSION 3;
+    LIMIT H_FLOATING;
+    OWNER LONGWORD UNSIGNED;
+END ACCOUNT;
+`
+	p := newSDLParser()
+	if err := p.parse(strings.NewReader(src), "x.sdl"); err != nil {
+		t.Fatalf("parse raised unexpected error: %q", err)
+	}
+	got, err := generateTags("records", []string{"x.sdl"}, p.records)
+	if err != nil {
+		t.Fatalf("generateTags raised unexpected error: %q", err)
+	}
+
+	want := `// Code generated by vaxgen from x.sdl; DO NOT EDIT.
+
+package records
+
+import "github.com/sixlettervariables/vaxdata"
+
+// Account is the layout of AGGREGATE ACCOUNT.
+type Account struct {
+	Balance [4]byte // DECIMAL PRECISION (7,2), see vaxdata.DecodePackedDecimal
+	Name    string  ` + "`" + `vax:"char,10"` + "`" + ` // CHARACTER LENGTH 10
+	_       [2]byte
+	Rates   [3]float32        ` + "`" + `vax:"f"` + "`" + ` // F_FLOATING
+	Limit   vaxdata.VaxHFloat // H_FLOATING
+	Owner   uint32            ` + "`" + `vax:"l"` + "`" + ` // LONGWORD UNSIGNED
+}
+`
+	if string(got) != want {
+		t.Errorf("generateTags ==\n%s\nwant\n%s", got, want)
+	}
+
+	records := parseFile(t, "testdata/sample.sdl")
+	if _, err := generateTags("records", []string{"sample.sdl"}, records); err == nil {
+		t.Errorf("generateTags of a record with a UNION did not raise an error")
+	}
+}
+
+func TestSDLErrors(t *testing.T) {
+	var tests = []struct {
+		src, err string
+	}{
+		{"AGGREGATE A STRUCTURE;\n X ANY;\nEND A;\n", "x.sdl:2: ANY is not supported"},
+		{"AGGREGATE A STRUCTURE;\n X H_FLOATING COMPLEX;\nEND A;\n", "x.sdl:2: H_FLOATING COMPLEX is not supported"},
+		{"AGGREGATE A STRUCTURE;\n X CHARACTER LENGTH 4 VARYING;\nEND A;\n", "x.sdl:2: CHARACTER VARYING is not supported"},
+		{"AGGREGATE A STRUCTURE;\n X CHARACTER LENGTH 0;\nEND A;\n", "x.sdl:2: invalid CHARACTER LENGTH 0"},
+		{"AGGREGATE A STRUCTURE;\n X LONGWORD DIMENSION N;\nEND A;\n", "x.sdl:2: N is not an integer constant"},
+		{"AGGREGATE A STRUCTURE;\n X LONGWORD;\nEND B;\n", "x.sdl:3: END B in A"},
+		{"AGGREGATE A STRUCTURE;\n X LONGWORD;\n", "x.sdl:2: missing END A"},
+		{"AGGREGATE A STRUCTURE;\n X LONGWORD NOSUCH;\nEND A;\n", "x.sdl:2: unknown option NOSUCH"},
+		{"AGGREGATE A STRUCTURE;\n X BITFIELD LENGTH 24;\nEND A;\n", "x.sdl:3: BITFIELD X is 3 bytes, which is not an integer size"},
+		{"AGGREGATE A STRUCTURE;\n X LONGWORD\nEND A;\n", "x.sdl:2: unknown option END"},
+		{"AGGREGATE A STRUCTURE;\n X LONGWORD", "x.sdl:2: missing ;"},
+		{"AGGREGATE A STRUCTURE;\n X CHARACTER LENGTH %X1G;\nEND A;\n", `x.sdl:2: malformed number "%X1G"`},
+		{"AGGREGATE A STRUCTURE;\n\n X \"NAME;\n", "x.sdl:3: unterminated string"},
+	}
+
+	for _, tt := range tests {
+		p := newSDLParser()
+		err := p.parse(strings.NewReader(tt.src), "x.sdl")
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("parse(%q) raised %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package vaxdata
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// VaxFComplex represents a VAX F_Complex value, a pair of F_Float's holding
+// the real and imaginary parts (FORTRAN COMPLEX*8)
+type VaxFComplex struct {
+	Real, Imag VaxFFloat
+}
+
+// VaxDComplex represents a VAX D_Complex value, a pair of D_Float's holding
+// the real and imaginary parts (FORTRAN COMPLEX*16)
+type VaxDComplex struct {
+	Real, Imag VaxDFloat
+}
+
+// VaxGComplex represents a VAX G_Complex value, a pair of G_Float's holding
+// the real and imaginary parts (FORTRAN COMPLEX*16 under /G_FLOATING)
+type VaxGComplex struct {
+	Real, Imag VaxGFloat
+}
+
+// complexError labels the errors from converting each part of a complex value.
+func complexError(format string, re, im error) error {
+	if re != nil {
+		re = fmt.Errorf("%s real part: %w", format, re)
+	}
+	if im != nil {
+		im = fmt.Errorf("%s imaginary part: %w", format, im)
+	}
+	return errors.Join(re, im)
+}
+
+// VaxFComplexReader reads complex64 values from F_Complex's in the underlying
+// io.Reader.
+type VaxFComplexReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// NewVaxFComplexReader creates a new VaxFComplexReader. VaxFComplexReader.Read
+// reads a complex64 from a F_Complex in the underlying io.Reader.
+func NewVaxFComplexReader(r io.Reader) *VaxFComplexReader {
+	vaxin := new(VaxFComplexReader)
+	(*vaxin).r = r
+	(*vaxin).buf = make([]byte, 8)
+	return vaxin
+}
+
+// Read takes a F_Complex from the underlying io.Reader and returns a complex64.
+func (vaxin *VaxFComplexReader) Read() (complex64, error) {
+	if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+		return 0, err
+	}
+	return Complex64fromVaxFComplex(vaxin.buf)
+}
+
+// Complex64fromVaxFComplex returns the complex64 representation of a VAX
+// F_Complex.
+func Complex64fromVaxFComplex(buf []byte) (complex64, error) {
+	re, reErr := Float32fromVaxFFloat(buf[0:4])
+	im, imErr := Float32fromVaxFFloat(buf[4:8])
+	return complex(re, im), complexError("F_Complex", reErr, imErr)
+}
+
+// VaxFComplexfromComplex64 returns the VAX F_Complex representation of a
+// complex64.
+func VaxFComplexfromComplex64(c complex64) (VaxFComplex, error) {
+	re, reErr := VaxFFloatfromFloat32(real(c))
+	im, imErr := VaxFFloatfromFloat32(imag(c))
+	return VaxFComplex{re, im}, complexError("F_Complex", reErr, imErr)
+}
+
+// WriteFComplex takes a complex64 and writes an F_Complex to the io.Writer.
+func WriteFComplex(w io.Writer, c complex64) error {
+	v, err := VaxFComplexfromComplex64(c)
+	if err != nil {
+		return err
+	}
+
+	return binary.Write(w, binary.BigEndian, [2]uint32{uint32(v.Real), uint32(v.Imag)})
+}
+
+// VaxDComplexReader reads complex128 values from D_Complex's in the
+// underlying io.Reader.
+type VaxDComplexReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// NewVaxDComplexReader creates a new VaxDComplexReader. VaxDComplexReader.Read
+// reads a complex128 from a D_Complex in the underlying io.Reader.
+func NewVaxDComplexReader(r io.Reader) *VaxDComplexReader {
+	vaxin := new(VaxDComplexReader)
+	(*vaxin).r = r
+	(*vaxin).buf = make([]byte, 16)
+	return vaxin
+}
+
+// Read takes a D_Complex from the underlying io.Reader and returns a
+// complex128.
+func (vaxin *VaxDComplexReader) Read() (complex128, error) {
+	if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+		return 0, err
+	}
+	return Complex128fromVaxDComplex(vaxin.buf)
+}
+
+// Complex128fromVaxDComplex returns the complex128 representation of a VAX
+// D_Complex.
+func Complex128fromVaxDComplex(buf []byte) (complex128, error) {
+	re, reErr := Float64fromVaxDFloat(buf[0:8])
+	im, imErr := Float64fromVaxDFloat(buf[8:16])
+	return complex(re, im), complexError("D_Complex", reErr, imErr)
+}
+
+// VaxDComplexfromComplex128 returns the VAX D_Complex representation of a
+// complex128.
+func VaxDComplexfromComplex128(c complex128) (VaxDComplex, error) {
+	re, reErr := VaxDFloatfromFloat64(real(c))
+	im, imErr := VaxDFloatfromFloat64(imag(c))
+	return VaxDComplex{re, im}, complexError("D_Complex", reErr, imErr)
+}
+
+// WriteDComplex takes a complex128 and writes a D_Complex to the io.Writer.
+func WriteDComplex(w io.Writer, c complex128) error {
+	v, err := VaxDComplexfromComplex128(c)
+	if err != nil {
+		return err
+	}
+
+	return binary.Write(w, binary.BigEndian, [2]uint64{uint64(v.Real), uint64(v.Imag)})
+}
+
+// VaxGComplexReader reads complex128 values from G_Complex's in the
+// underlying io.Reader.
+type VaxGComplexReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// NewVaxGComplexReader creates a new VaxGComplexReader. VaxGComplexReader.Read
+// reads a complex128 from a G_Complex in the underlying io.Reader.
+func NewVaxGComplexReader(r io.Reader) *VaxGComplexReader {
+	vaxin := new(VaxGComplexReader)
+	(*vaxin).r = r
+	(*vaxin).buf = make([]byte, 16)
+	return vaxin
+}
+
+// Read takes a G_Complex from the underlying io.Reader and returns a
+// complex128.
+func (vaxin *VaxGComplexReader) Read() (complex128, error) {
+	if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+		return 0, err
+	}
+	return Complex128fromVaxGComplex(vaxin.buf)
+}
+
+// Complex128fromVaxGComplex returns the complex128 representation of a VAX
+// G_Complex.
+func Complex128fromVaxGComplex(buf []byte) (complex128, error) {
+	re, reErr := Float64fromVaxGFloat(buf[0:8])
+	im, imErr := Float64fromVaxGFloat(buf[8:16])
+	return complex(re, im), complexError("G_Complex", reErr, imErr)
+}
+
+// VaxGComplexfromComplex128 returns the VAX G_Complex representation of a
+// complex128.
+func VaxGComplexfromComplex128(c complex128) (VaxGComplex, error) {
+	re, reErr := VaxGFloatfromFloat64(real(c))
+	im, imErr := VaxGFloatfromFloat64(imag(c))
+	return VaxGComplex{re, im}, complexError("G_Complex", reErr, imErr)
+}
+
+// WriteGComplex takes a complex128 and writes a G_Complex to the io.Writer.
+func WriteGComplex(w io.Writer, c complex128) error {
+	v, err := VaxGComplexfromComplex128(c)
+	if err != nil {
+		return err
+	}
+
+	return binary.Write(w, binary.BigEndian, [2]uint64{uint64(v.Real), uint64(v.Imag)})
+}
@@ -0,0 +1,75 @@
+package vaxdata
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestVaxComplex(t *testing.T) {
+	f := []byte{0x00, 0x00, 0x40, 0x80, 0x0F, 0xD0, 0xC1, 0x49}
+	var buf bytes.Buffer
+	if err := WriteFComplex(&buf, complex(1, -3.14159)); err != nil {
+		t.Errorf("WriteFComplex raised unexpected error: %q", err)
+	} else if !sliceByteEquals(buf.Bytes(), f) {
+		t.Errorf("WriteFComplex == %X, want %X", buf.Bytes(), f)
+	}
+	if c, err := NewVaxFComplexReader(&buf).Read(); err != nil || c != complex(1, -3.14159) {
+		t.Errorf("VaxFComplexReader.Read(%X) == %v, %v", f, c, err)
+	}
+
+	g := []byte{
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xC0, 0x10,
+		0x2D, 0x18, 0x54, 0x44, 0x21, 0xFB, 0x40, 0x29,
+	}
+	buf.Reset()
+	if err := WriteGComplex(&buf, complex(-1, math.Pi)); err != nil {
+		t.Errorf("WriteGComplex raised unexpected error: %q", err)
+	} else if !sliceByteEquals(buf.Bytes(), g) {
+		t.Errorf("WriteGComplex == %X, want %X", buf.Bytes(), g)
+	}
+	if c, err := NewVaxGComplexReader(&buf).Read(); err != nil || c != complex(-1, math.Pi) {
+		t.Errorf("VaxGComplexReader.Read(%X) == %v, %v", g, c, err)
+	}
+
+	d := []byte{
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xC0, 0x80,
+		0x68, 0xC0, 0xA2, 0x21, 0x0F, 0xDA, 0x41, 0x49,
+	}
+	buf.Reset()
+	if err := WriteDComplex(&buf, complex(-1, math.Pi)); err != nil {
+		t.Errorf("WriteDComplex raised unexpected error: %q", err)
+	} else if !sliceByteEquals(buf.Bytes(), d) {
+		t.Errorf("WriteDComplex == %X, want %X", buf.Bytes(), d)
+	}
+	if c, err := NewVaxDComplexReader(&buf).Read(); err != nil || c != complex(-1, math.Pi) {
+		t.Errorf("VaxDComplexReader.Read(%X) == %v, %v", d, c, err)
+	}
+}
+
+func TestVaxComplexReservedOperand(t *testing.T) {
+	cases := []struct {
+		in   []byte
+		want []string
+	}{
+		{[]byte{0x00, 0x00, 0x80, 0x00, 0x00, 0x00, 0x40, 0x80}, []string{"real part"}},
+		{[]byte{0x00, 0x00, 0x40, 0x80, 0x00, 0x00, 0x80, 0x00}, []string{"imaginary part"}},
+		{[]byte{0x00, 0x00, 0x80, 0x00, 0x00, 0x00, 0x80, 0x00}, []string{"real part", "imaginary part"}},
+	}
+	for _, c := range cases {
+		_, err := Complex64fromVaxFComplex(c.in)
+		if err == nil {
+			t.Errorf("Complex64fromVaxFComplex(%X) did not raise an error", c.in)
+			continue
+		}
+		for _, want := range c.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("Complex64fromVaxFComplex(%X) error %q does not mention %q", c.in, err, want)
+			}
+		}
+		if len(c.want) == 1 && strings.Contains(err.Error(), "\n") {
+			t.Errorf("Complex64fromVaxFComplex(%X) error %q reports both parts", c.in, err)
+		}
+	}
+}
@@ -30,9 +30,15 @@ import (
 // VaxFFloat represents a VAX F_Float 32-bit value
 type VaxFFloat uint32
 
+// VaxDFloat represents a VAX D_Float 64-bit value
+type VaxDFloat uint64
+
 // VaxGFloat represents a VAX G_Float 64-bit value
 type VaxGFloat uint64
 
+// VaxHFloat represents a VAX H_Float 128-bit value
+type VaxHFloat [16]byte
+
 // Floating point data format invariants
 //
 // (reproduced from convert_vax_data.h)
@@ -89,6 +95,7 @@ type VaxGFloat uint64
 // 	simply discarded from the right.  Thus, the remaining fractional part
 // 	is chopped, not rounded to the lowest-order bit.  This can only occur
 // 	when the conversion requires IEEE subnormal form.
+// 	A Converter with the Rounding option rounds these bits instead.
 //
 // A  VAX  floating-point  reserved operand [s=1, e=0, m=any] causes a SIGFPE
 // exception to be raised.  The converted result is set to zero.
@@ -123,6 +130,13 @@ const (
 	VaxFMantissaSize uint32 = 23
 	VaxFHiddenBit    uint32 = (1 << VaxFMantissaSize)
 
+	VaxDExponentMask uint32 = 0x7F800000
+	VaxDExponentSize uint32 = 8
+	VaxDExponentBias uint32 = (1 << (VaxDExponentSize - 1))
+	VaxDMantissaMask uint32 = 0x007FFFFF
+	VaxDMantissaSize uint32 = 23
+	VaxDHiddenBit    uint32 = (1 << VaxDMantissaSize)
+
 	VaxGExponentMask uint32 = 0x7FF00000
 	VaxGExponentSize uint32 = 11
 	VaxGExponentBias uint32 = (1 << (VaxGExponentSize - 1))
@@ -130,6 +144,13 @@ const (
 	VaxGMantissaSize uint32 = 20
 	VaxGHiddenBit    uint32 = (1 << VaxGMantissaSize)
 
+	VaxHExponentMask uint32 = 0x7FFF0000
+	VaxHExponentSize uint32 = 15
+	VaxHExponentBias uint32 = (1 << (VaxHExponentSize - 1))
+	VaxHMantissaMask uint32 = 0x0000FFFF
+	VaxHMantissaSize uint32 = 16
+	VaxHHiddenBit    uint32 = (1 << VaxHMantissaSize)
+
 	// IEEE floating point data formats (see Alpha Architecture Reference Manual)
 
 	IeeeSExponentMask uint32 = 0x7F800000
@@ -5,14 +5,18 @@
 // original USGS work released into the public domain.
 //
 
-// Provides tests for the conversions to-and-from VAX F_ and G_floats
+// Provides tests for the conversions to-and-from VAX F_, D_, G_ and H_floats
 package vaxdata
 
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"math/big"
 	"testing"
 )
 
@@ -54,6 +58,66 @@ func TestVaxFFloat(t *testing.T) {
 	}
 }
 
+func TestVaxFFloatUnderflow(t *testing.T) {
+	// S_Float subnormals and values below 2**-128 flush to VAX zero. The
+	// unsigned exponent arithmetic of the original code wrapped these to
+	// overflow.
+	cases := []struct {
+		in   float32
+		want string
+	}{
+		{math.SmallestNonzeroFloat32, "00000000"},
+		{-math.SmallestNonzeroFloat32, "00000000"},
+		{0x1p-129, "00000000"},
+		{0x1p-128, "00000080"},
+	}
+
+	for _, c := range cases {
+		got, err := VaxFFloatfromFloat32(c.in)
+		if err != nil {
+			t.Errorf("VaxFFloat32fromFloat32(%g) raised unexpected error: %q", c.in, err)
+		} else if fmt.Sprintf("%08X", got) != c.want {
+			t.Errorf("VaxFFloat32fromFloat32(%g) == %08X, want %s", c.in, got, c.want)
+		}
+	}
+}
+
+func TestVaxFFloatfromFloat64(t *testing.T) {
+	cases := []struct {
+		in   float64
+		want VaxFFloat
+		err  error
+	}{
+		{float64(float32(3.141590)), 0x0FD04149, nil},
+		{-1, 0x0000C080, nil},
+		{1 - 0x1p-30, 0x00004080, nil},       // rounds up to 1
+		{0x1.000002p-127, 0x00010100, nil},   // a float32 subnormal loses the last bit
+		{0x1.ffffffffp-129, 0x00000080, nil}, // rounds up to the smallest F_Float
+		{1e-40, 0, nil},                      // underflows to zero
+		{0x1p127, 0xFFFF7FFF, ErrOverflow},   // just above the largest F_Float
+		{-1e39, 0xFFFFFFFF, ErrOverflow},     // too large for a float32 too
+		{math.Inf(1), 0x00007F80, ErrNotFinite},
+	}
+
+	for _, c := range cases {
+		got, err := VaxFFloatfromFloat64(c.in)
+		if !errors.Is(err, c.err) || (err == nil) != (c.err == nil) {
+			t.Errorf("VaxFFloatfromFloat64(%g) raised %v, want %v", c.in, err, c.err)
+		}
+		if got != c.want {
+			t.Errorf("VaxFFloatfromFloat64(%g) == %08X, want %08X", c.in, uint32(got), uint32(c.want))
+		}
+	}
+
+	// float32 values convert exactly, as by VaxFFloatfromFloat32
+	for _, f := range []float32{3.141590, -9.9999999e-38, 1.234568, math.MaxFloat32} {
+		want, _ := VaxFFloatfromFloat32(f)
+		if got, _ := VaxFFloatfromFloat64(float64(f)); got != want {
+			t.Errorf("VaxFFloatfromFloat64(%g) == %08X, want %08X", f, uint32(got), uint32(want))
+		}
+	}
+}
+
 func TestVaxFFloatbits(t *testing.T) {
 	cases := []struct {
 		ieee []float32
@@ -103,6 +167,21 @@ func TestVaxFFloatbits(t *testing.T) {
 		if !sliceByteEquals(buf.Bytes(), c.vaxf) {
 			t.Errorf("WriteFFloat(%v) == %v, want %v", c.ieee, buf.Bytes(), c.vaxf)
 		}
+
+		buf.Reset()
+		w := NewVaxFFloatWriter(&buf)
+		if n, err := w.WriteSlice(c.ieee); n != len(c.ieee) || err != nil {
+			t.Errorf("VaxFFloatWriter.WriteSlice(%v) == %d, %v", c.ieee, n, err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("VaxFFloatWriter.WriteSlice(%v) was not buffered", c.ieee)
+		}
+		if err := w.Flush(); err != nil {
+			t.Errorf("VaxFFloatWriter.Flush() raised unexpected error: %q", err)
+		}
+		if !sliceByteEquals(buf.Bytes(), c.vaxf) || w.Count() != int64(len(c.ieee)) {
+			t.Errorf("VaxFFloatWriter.WriteSlice(%v) == %v (%d values), want %v", c.ieee, buf.Bytes(), w.Count(), c.vaxf)
+		}
 	}
 }
 
@@ -143,6 +222,28 @@ func TestVaxGFloat(t *testing.T) {
 	}
 }
 
+func TestVaxGFloatUnderflow(t *testing.T) {
+	// T_Float subnormals and values below 2**-1024 flush to VAX zero
+	cases := []struct {
+		in   float64
+		want string
+	}{
+		{math.SmallestNonzeroFloat64, "0000000000000000"},
+		{-math.SmallestNonzeroFloat64, "0000000000000000"},
+		{0x1p-1025, "0000000000000000"},
+		{0x1p-1024, "0000000000000010"},
+	}
+
+	for _, c := range cases {
+		got, err := VaxGFloatfromFloat64(c.in)
+		if err != nil {
+			t.Errorf("VaxGFloat64fromFloat64(%g) raised unexpected error: %q", c.in, err)
+		} else if fmt.Sprintf("%016X", got) != c.want {
+			t.Errorf("VaxGFloat64fromFloat64(%g) == %016X, want %s", c.in, got, c.want)
+		}
+	}
+}
+
 func TestVaxGFloatbits(t *testing.T) {
 	cases := []struct {
 		ieee []float64
@@ -192,6 +293,220 @@ func TestVaxGFloatbits(t *testing.T) {
 		if !sliceByteEquals(buf.Bytes(), c.vaxg) {
 			t.Errorf("VaxGFloatWriter.Write(%v) == %v, want %v", c.ieee, buf.Bytes(), c.vaxg)
 		}
+
+		buf.Reset()
+		w := NewVaxGFloatWriter(&buf)
+		if n, err := w.WriteSlice(c.ieee); n != len(c.ieee) || err != nil {
+			t.Errorf("VaxGFloatWriter.WriteSlice(%v) == %d, %v", c.ieee, n, err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("VaxGFloatWriter.WriteSlice(%v) was not buffered", c.ieee)
+		}
+		if err := w.Flush(); err != nil {
+			t.Errorf("VaxGFloatWriter.Flush() raised unexpected error: %q", err)
+		}
+		if !sliceByteEquals(buf.Bytes(), c.vaxg) || w.Count() != int64(len(c.ieee)) {
+			t.Errorf("VaxGFloatWriter.WriteSlice(%v) == %v (%d values), want %v", c.ieee, buf.Bytes(), w.Count(), c.vaxg)
+		}
+	}
+}
+
+func TestVaxDFloat(t *testing.T) {
+	cases := []struct {
+		in   float64
+		want string
+	}{
+		{1.000000000000000, "0000000000004080"},
+		{-1.000000000000000, "000000000000C080"},
+		{3.500000000000000, "0000000000004160"},
+		{-3.500000000000000, "000000000000C160"},
+		{3.141592653589793, "68C0A2210FDA4149"},
+		{-3.141592653589793, "68C0A2210FDAC149"},
+		{1.0000000000000000E+37, "48D81ABBBDC27DF0"},
+		{-1.0000000000000000E+37, "48D81ABBBDC2FDF0"},
+		{9.9999999999999999E-38, "5C7814541CEA0308"},
+		{-9.9999999999999999E-38, "5C7814541CEA8308"},
+		{1.23456789012345, "CEE814620652409E"},
+		{-1.23456789012345, "CEE814620652C09E"},
+	}
+	buf := make([]byte, 8)
+	for _, c := range cases {
+		got, err := VaxDFloatfromFloat64(c.in)
+		if err != nil {
+			t.Errorf("VaxDFloatfromFloat64(%g) raised unexpected error: %q", c.in, err)
+		} else if fmt.Sprintf("%016X", got) != c.want {
+			t.Errorf("VaxDFloatfromFloat64(%g) == %016X, want %s", c.in, got, c.want)
+		}
+
+		binary.BigEndian.PutUint64(buf, uint64(got))
+		reverse, err := Float64fromVaxDFloat(buf)
+		if err != nil {
+			t.Errorf("Float64fromVaxDFloat(%016X) raised unexpected error: %q", got, err)
+		} else if reverse != c.in {
+			t.Errorf("Float64fromVaxDFloat(%016X) == %g, want %g", got, reverse, c.in)
+		}
+	}
+}
+
+func TestVaxDFloatbits(t *testing.T) {
+	cases := []struct {
+		ieee []float64
+		vaxd []byte
+	}{
+		{[]float64{1.000000000000000, -1.000000000000000}, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xC0, 0x80}},
+		{[]float64{3.141592653589793, -3.141592653589793}, []byte{0x68, 0xC0, 0xA2, 0x21, 0x0F, 0xDA, 0x41, 0x49, 0x68, 0xC0, 0xA2, 0x21, 0x0F, 0xDA, 0xC1, 0x49}},
+		{[]float64{1.23456789012345, -1.23456789012345}, []byte{0xCE, 0xE8, 0x14, 0x62, 0x06, 0x52, 0x40, 0x9E, 0xCE, 0xE8, 0x14, 0x62, 0x06, 0x52, 0xC0, 0x9E}},
+	}
+	for _, c := range cases {
+		// test using a reader
+		n := 0
+		r := NewVaxDFloatReader(bytes.NewBuffer(c.vaxd))
+		v, err := r.Read()
+		for err == nil {
+			if c.ieee[n] != v {
+				t.Errorf("VaxDFloatReader.Read(%v) == %v, want %v", c.vaxd[n*8:n*8+8], v, c.ieee[n])
+			}
+			v, err = r.Read()
+			n++
+		}
+		if err != nil && err != io.EOF {
+			t.Errorf("Unexpected error %q", err)
+		}
+
+		var buf bytes.Buffer
+		for _, f := range c.ieee {
+			err := WriteDFloat(&buf, f)
+			if err != nil {
+				t.Errorf("WriteDFloat(%v) raised unexpected error: %q", f, err)
+			}
+		}
+		if !sliceByteEquals(buf.Bytes(), c.vaxd) {
+			t.Errorf("WriteDFloat(%v) == %v, want %v", c.ieee, buf.Bytes(), c.vaxd)
+		}
+
+		buf.Reset()
+		w := NewVaxDFloatWriter(&buf)
+		if n, err := w.WriteSlice(c.ieee); n != len(c.ieee) || err != nil {
+			t.Errorf("VaxDFloatWriter.WriteSlice(%v) == %d, %v", c.ieee, n, err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("VaxDFloatWriter.WriteSlice(%v) was not buffered", c.ieee)
+		}
+		if err := w.Flush(); err != nil {
+			t.Errorf("VaxDFloatWriter.Flush() raised unexpected error: %q", err)
+		}
+		if !sliceByteEquals(buf.Bytes(), c.vaxd) || w.Count() != int64(len(c.ieee)) {
+			t.Errorf("VaxDFloatWriter.WriteSlice(%v) == %v (%d values), want %v", c.ieee, buf.Bytes(), w.Count(), c.vaxd)
+		}
+	}
+}
+
+func TestVaxDFloatRounding(t *testing.T) {
+	// The low three bits of each D_Float are lost converting to float64;
+	// compare against the exact value rounded by math/big.
+	cases := []uint64{
+		0x0001000000004080, // 1 + 2^-55: below half, rounds down
+		0x0004000000004080, // 1 + 2^-53: exactly half, ties to even (down)
+		0x000C000000004080, // 1 + 3*2^-53: exactly half, ties to even (up)
+		0x0005000000004080, // 1 + 5*2^-55: above half, rounds up
+		0xFFFFFFFFFFFF7FFF, // largest D_Float: carries into the exponent
+		0xFFFFFFFFFFFFFFFF, // largest negative D_Float
+		0x0000000000000080, // smallest D_Float
+	}
+	buf := make([]byte, 8)
+	for _, c := range cases {
+		binary.BigEndian.PutUint64(buf, c)
+		got, err := Float64fromVaxDFloat(buf)
+		if err != nil {
+			t.Errorf("Float64fromVaxDFloat(%016X) raised unexpected error: %q", c, err)
+			continue
+		}
+
+		vaxpart1 := uint32FromVaxbits(buf[4:8])
+		vaxpart2 := uint32FromVaxbits(buf[:4])
+		m := new(big.Int).SetUint64(uint64(vaxpart1&VaxDMantissaMask|VaxDHiddenBit)<<32 | uint64(vaxpart2))
+		e := int((vaxpart1&VaxDExponentMask)>>VaxDMantissaSize) - int(VaxDExponentBias) - 56
+		exact := new(big.Float).SetMantExp(new(big.Float).SetInt(m), e)
+		if vaxpart1&SignBit != 0 {
+			exact.Neg(exact)
+		}
+		want, _ := exact.Float64()
+		if got != want {
+			t.Errorf("Float64fromVaxDFloat(%016X) == %v, want %v", c, got, want)
+		}
+	}
+}
+
+func TestVaxHFloat(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"1", "00000000000000000000000000004001"},
+		{"-1", "0000000000000000000000000000C001"},
+		{"3.5", "000000000000000000000000C0004002"},
+		{"3.14159265358979323846264338327950288419716939937510", "01B8C517898C846942D1B544921F4002"},
+		{"-3.14159265358979323846264338327950288419716939937510", "01B8C517898C846942D1B544921FC002"},
+		{"1e100", "E0BF4C4CB278CEB04C37D259249A414D"},
+		{"1e-100", "2779FC57B2F0FD7AE052EE48BFF23EB4"},
+		{"0.1", "999A9999999999999999999999993FFD"},
+	}
+	for _, c := range cases {
+		in, _, err := big.ParseFloat(c.in, 10, 256, big.ToNearestEven)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := VaxHFloatfromBigFloat(in)
+		if err != nil {
+			t.Errorf("VaxHFloatfromBigFloat(%s) raised unexpected error: %q", c.in, err)
+		} else if fmt.Sprintf("%X", got[:]) != c.want {
+			t.Errorf("VaxHFloatfromBigFloat(%s) == %X, want %s", c.in, got[:], c.want)
+		}
+
+		reverse, err := BigFloatfromVaxHFloat(got[:])
+		want := new(big.Float).SetPrec(VaxHFloatPrec).Set(in)
+		if err != nil {
+			t.Errorf("BigFloatfromVaxHFloat(%X) raised unexpected error: %q", got[:], err)
+		} else if reverse.Cmp(want) != 0 {
+			t.Errorf("BigFloatfromVaxHFloat(%X) == %v, want %v", got[:], reverse, want)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteHFloat(&buf, in); err != nil {
+			t.Errorf("WriteHFloat(%s) raised unexpected error: %q", c.in, err)
+		}
+		r := NewVaxHFloatReader(&buf)
+		if v, err := r.Read(); err != nil || v.Cmp(want) != 0 {
+			t.Errorf("VaxHFloatReader.Read(%X) == %v, %v, want %v", got[:], v, err, want)
+		}
+	}
+}
+
+func TestVaxHFloat64(t *testing.T) {
+	cases := []struct {
+		in   string
+		want float64
+		acc  big.Accuracy
+		err  bool
+	}{
+		{"00000000000000000000000000004001", 1, big.Exact, false},
+		{"01B8C517898C846942D1B544921F4002", math.Pi, big.Below, false},
+		{"00000000000000000000000000008000", 0, big.Exact, true},
+		{"00000000000000000000000000010000", 0, big.Exact, false},
+		{"00000000000000000000000000007FFF", math.Inf(1), big.Above, true},
+		{"0000000000000000000000000000FFFF", math.Inf(-1), big.Below, true},
+		{"00000000000000000000000000000001", 0, big.Below, true},
+	}
+	for _, c := range cases {
+		buf, _ := hex.DecodeString(c.in)
+		got, acc, err := Float64fromVaxHFloat(buf)
+		if (err != nil) != c.err {
+			t.Errorf("Float64fromVaxHFloat(%s) error == %v, want error %v", c.in, err, c.err)
+		}
+		if got != c.want || acc != c.acc {
+			t.Errorf("Float64fromVaxHFloat(%s) == %v, %v, want %v, %v", c.in, got, acc, c.want, c.acc)
+		}
 	}
 }
 
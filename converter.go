@@ -0,0 +1,371 @@
+package vaxdata
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+)
+
+// Action selects how a Converter handles a value that has no exact
+// equivalent in the format converted to.
+type Action int
+
+const (
+	// Default returns the fixup value of the package functions, with the
+	// *ConversionError they return, if any. Dirty zeros and underflow to a
+	// VAX format become zero without an error.
+	Default Action = iota
+
+	// Report returns the fixup value of the package functions with a
+	// *ConversionError, including for dirty zeros and underflow.
+	Report
+
+	// Ignore returns the fixup value of the package functions without an
+	// error.
+	Ignore
+
+	// ToZero returns zero without an error.
+	ToZero
+
+	// ToNaN returns NaN, or the VAX reserved operand when converting to a
+	// VAX format, without an error.
+	ToNaN
+
+	// Saturate returns the largest finite value with the sign of the input
+	// without an error. It does not apply to reserved operands, dirty zeros
+	// or underflow.
+	Saturate
+
+	// ToNearest returns the nearer of zero and the smallest VAX normalized
+	// value with the sign of the input, ties going to zero, without an
+	// error. It applies only to underflow.
+	ToNearest
+)
+
+func (a Action) String() string {
+	switch a {
+	case Default:
+		return "Default"
+	case Report:
+		return "Report"
+	case Ignore:
+		return "Ignore"
+	case ToZero:
+		return "ToZero"
+	case ToNaN:
+		return "ToNaN"
+	case Saturate:
+		return "Saturate"
+	case ToNearest:
+		return "ToNearest"
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// Converter converts between IEEE and VAX floating point formats, handling
+// the values that have no exact equivalent as set by its options. The zero
+// Converter behaves as the package functions.
+type Converter struct {
+	reserved, dirtyZero           Action // from VAX formats
+	overflow, nan, inf, underflow Action // to VAX formats
+
+	mode     big.RoundingMode // set by Rounding
+	rounding bool
+
+	onError ErrorHandler // for readers
+}
+
+// Option sets the Action a Converter takes for one kind of value.
+type Option func(*Converter)
+
+// OnReservedOperand sets the Action for VAX reserved operands, which the
+// package functions convert to zero with ErrReservedOperand.
+func OnReservedOperand(a Action) Option { return func(c *Converter) { c.reserved = a } }
+
+// OnDirtyZero sets the Action for VAX dirty zeros, which the package
+// functions convert to zero. Report returns ErrDirtyZero.
+func OnDirtyZero(a Action) Option { return func(c *Converter) { c.dirtyZero = a } }
+
+// OnOverflow sets the Action for values too large for the VAX format, which
+// the package functions convert to the VAX extrema with ErrOverflow.
+func OnOverflow(a Action) Option { return func(c *Converter) { c.overflow = a } }
+
+// OnNaN sets the Action for NaN, which the package functions convert to the
+// VAX extrema with a zero fraction and ErrNotFinite.
+func OnNaN(a Action) Option { return func(c *Converter) { c.nan = a } }
+
+// OnInf sets the Action for +-Infinity, which the package functions convert
+// to the VAX extrema with a zero fraction and ErrNotFinite.
+func OnInf(a Action) Option { return func(c *Converter) { c.inf = a } }
+
+// OnUnderflow sets the Action for non-zero values too small for the VAX
+// format, which the package functions convert to zero. Report returns
+// ErrUnderflow, with the value converted as the Input of the
+// *ConversionError. Unlike the other errors it is not fatal: the zero
+// returned stands for the value, and the writers created by the Converter
+// write it.
+func OnUnderflow(a Action) Option { return func(c *Converter) { c.underflow = a } }
+
+// Rounding sets the rounding mode of the conversions that can lose bits:
+// F_Float and G_Float values that need IEEE subnormal form, which the
+// package functions chop (big.ToZero); D_Float and H_Float to float64,
+// float64 to F_Float and *big.Float to H_Float, which they round to
+// nearest, ties to even; and values too small for a VAX format, which they
+// flush to zero. Under Rounding these round to zero or to the smallest VAX
+// normalized value, ties going to zero but for big.ToNearestAway, before
+// the OnUnderflow Action applies. Conversions from float32 to F_Float and
+// from float64 to D_Float and G_Float are otherwise exact.
+//
+// The package functions, and the struct codec and Scan methods built on
+// them, always round as described above.
+func Rounding(mode big.RoundingMode) Option {
+	return func(c *Converter) { c.mode, c.rounding = mode, true }
+}
+
+// OnReadError sets the ErrorHandler called by the readers the Converter
+// creates for each value they cannot convert, after its Actions apply. The
+// handler may substitute a value or skip it to keep reading, or abort,
+// returning the error from Read. Read errors of the underlying io.Reader are
+// returned as is.
+//
+// The handler covers the F_Float, D_Float and G_Float readers only. There is
+// no Converter form of VaxHFloatReader, which returns every error from Read:
+// its *big.Float values have no NaN to substitute, and its 16 bytes do not
+// fit ReadFault.Raw.
+func OnReadError(h ErrorHandler) Option { return func(c *Converter) { c.onError = h } }
+
+// NewConverter returns a Converter with the given options.
+func NewConverter(opts ...Option) (*Converter, error) {
+	c := new(Converter)
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	for _, a := range []Action{c.reserved, c.dirtyZero, c.overflow, c.nan, c.inf, c.underflow} {
+		if a < Default || a > ToNearest {
+			return nil, fmt.Errorf("unknown %v", a)
+		}
+	}
+	if c.mode > big.ToPositiveInf {
+		return nil, fmt.Errorf("unknown %v", c.mode)
+	}
+	if c.reserved == Saturate || c.dirtyZero == Saturate || c.underflow == Saturate {
+		return nil, errors.New("Saturate does not apply to reserved operands, dirty zeros or underflow")
+	}
+	if c.reserved == ToNearest || c.dirtyZero == ToNearest || c.overflow == ToNearest || c.nan == ToNearest || c.inf == ToNearest {
+		return nil, errors.New("ToNearest applies only to underflow")
+	}
+	return c, nil
+}
+
+// Float32fromVaxFFloat returns the float32 representation of a VAX F_Float.
+func (c *Converter) Float32fromVaxFFloat(buf []byte) (float32, error) {
+	v := VaxFFloat(binary.BigEndian.Uint32(buf))
+	f, err := float32fromVaxFFloat(buf, c.roundingMode(big.ToZero))
+	return fromVax(c, "F_Float", v, v.Classify(), f, err)
+}
+
+// Float64fromVaxDFloat returns the float64 representation of a VAX D_Float.
+func (c *Converter) Float64fromVaxDFloat(buf []byte) (float64, error) {
+	v := VaxDFloat(binary.BigEndian.Uint64(buf))
+	f, err := float64fromVaxDFloat(buf, c.roundingMode(big.ToNearestEven))
+	return fromVax(c, "D_Float", v, v.Classify(), f, err)
+}
+
+// Float64fromVaxGFloat returns the float64 representation of a VAX G_Float.
+func (c *Converter) Float64fromVaxGFloat(buf []byte) (float64, error) {
+	v := VaxGFloat(binary.BigEndian.Uint64(buf))
+	f, err := float64fromVaxGFloat(buf, c.roundingMode(big.ToZero))
+	return fromVax(c, "G_Float", v, v.Classify(), f, err)
+}
+
+// Float64fromVaxHFloat returns the float64 nearest to a VAX H_Float by the
+// rounding mode of c. The big.Accuracy reports whether precision was lost.
+// Values of 2**1024 and above return ErrOverflow with +-Inf, or with
+// +-math.MaxFloat64 where the mode rounds toward zero.
+func (c *Converter) Float64fromVaxHFloat(buf []byte) (float64, big.Accuracy, error) {
+	v := VaxHFloat(buf[:16])
+	f, acc, err := float64fromVaxHFloat(buf, c.roundingMode(big.ToNearestEven))
+
+	part1 := uint32FromVaxbits(buf[12:16])
+	class := classify(uint(part1>>31), int((part1&VaxHExponentMask)>>VaxHMantissaSize), v != VaxHFloat{})
+
+	f, err = fromVax(c, "H_Float", v, class, f, err)
+	return f, acc, err
+}
+
+// VaxFFloatfromFloat32 returns the VAX F_Float representation of a float32.
+func (c *Converter) VaxFFloatfromFloat32(f float32) (VaxFFloat, error) {
+	v, err := VaxFFloatfromFloat32(f)
+	return toVax(c, "F_Float", f, v, err, vaxFLimits)
+}
+
+// VaxFFloatfromFloat64 returns the F_Float nearest to a float64 by the
+// rounding mode of c.
+func (c *Converter) VaxFFloatfromFloat64(f float64) (VaxFFloat, error) {
+	v, err := vaxFFloatfromFloat64(f, c.roundingMode(big.ToNearestEven))
+	return toVax(c, "F_Float", f, v, err, vaxFLimits)
+}
+
+// VaxDFloatfromFloat64 returns the VAX D_Float representation of a float64.
+func (c *Converter) VaxDFloatfromFloat64(f float64) (VaxDFloat, error) {
+	v, err := VaxDFloatfromFloat64(f)
+	return toVax(c, "D_Float", f, v, err, vaxDLimits)
+}
+
+// VaxGFloatfromFloat64 returns the VAX G_Float representation of a float64.
+func (c *Converter) VaxGFloatfromFloat64(f float64) (VaxGFloat, error) {
+	v, err := VaxGFloatfromFloat64(f)
+	return toVax(c, "G_Float", f, v, err, vaxGLimits)
+}
+
+// VaxHFloatfromBigFloat returns the VAX H_Float representation of a
+// *big.Float, rounded by the rounding mode of c. The Actions of c do not
+// apply; errors are those of the package function.
+func (c *Converter) VaxHFloatfromBigFloat(f *big.Float) (VaxHFloat, error) {
+	return vaxHFloatfromBigFloat(f, c.roundingMode(big.ToNearestEven))
+}
+
+// NewVaxFFloatReader creates a new VaxFFloatReader that converts with c.
+func (c *Converter) NewVaxFFloatReader(r io.Reader) *VaxFFloatReader {
+	vaxin := NewVaxFFloatReader(r)
+	vaxin.c = *c
+	return vaxin
+}
+
+// NewVaxDFloatReader creates a new VaxDFloatReader that converts with c.
+func (c *Converter) NewVaxDFloatReader(r io.Reader) *VaxDFloatReader {
+	vaxin := NewVaxDFloatReader(r)
+	vaxin.c = *c
+	return vaxin
+}
+
+// NewVaxGFloatReader creates a new VaxGFloatReader that converts with c.
+func (c *Converter) NewVaxGFloatReader(r io.Reader) *VaxGFloatReader {
+	vaxin := NewVaxGFloatReader(r)
+	vaxin.c = *c
+	return vaxin
+}
+
+// NewVaxFFloatWriter creates a new VaxFFloatWriter that converts with c.
+func (c *Converter) NewVaxFFloatWriter(w io.Writer) *VaxFFloatWriter {
+	vaxout := NewVaxFFloatWriter(w)
+	vaxout.c = *c
+	return vaxout
+}
+
+// NewVaxDFloatWriter creates a new VaxDFloatWriter that converts with c.
+func (c *Converter) NewVaxDFloatWriter(w io.Writer) *VaxDFloatWriter {
+	vaxout := NewVaxDFloatWriter(w)
+	vaxout.c = *c
+	return vaxout
+}
+
+// NewVaxGFloatWriter creates a new VaxGFloatWriter that converts with c.
+func (c *Converter) NewVaxGFloatWriter(w io.Writer) *VaxGFloatWriter {
+	vaxout := NewVaxGFloatWriter(w)
+	vaxout.c = *c
+	return vaxout
+}
+
+// roundingMode returns the mode set by Rounding, or def if it was not set.
+func (c *Converter) roundingMode(def big.RoundingMode) big.RoundingMode {
+	if c.rounding {
+		return c.mode
+	}
+	return def
+}
+
+// fromVax applies the Actions of c to the result f, err of converting the
+// VAX value v of the given Class.
+func fromVax[T VaxFloat, F float32 | float64](c *Converter, format string, v T, class Class, f F, err error) (F, error) {
+	var a Action
+	switch class {
+	case ClassReservedOperand:
+		a = c.reserved
+	case ClassDirtyZero:
+		if a = c.dirtyZero; a == Report {
+			err = &ConversionError{format, false, v, f, ErrDirtyZero}
+		}
+	default:
+		return f, err
+	}
+
+	switch a {
+	case Default, Report:
+		return f, err
+	case ToNaN:
+		return F(math.NaN()), nil
+	}
+	return 0, nil
+}
+
+// vaxLimits holds the largest and smallest positive normalized values of a
+// VAX format.
+type vaxLimits[T VaxFFloat | VaxDFloat | VaxGFloat] struct {
+	max, min T
+	minValue float64 // value of min
+}
+
+var (
+	vaxFLimits = vaxLimits[VaxFFloat]{0xFFFF7FFF, 0x00000080, 0x1p-128}
+	vaxDLimits = vaxLimits[VaxDFloat]{0xFFFFFFFFFFFF7FFF, 0x0000000000000080, 0x1p-128}
+	vaxGLimits = vaxLimits[VaxGFloat]{0xFFFFFFFFFFFF7FFF, 0x0000000000000010, 0x1p-1024}
+)
+
+// toVax applies the Actions of c to the result v, err of converting f to a
+// VAX format with the given limits.
+func toVax[F float32 | float64, T VaxFFloat | VaxDFloat | VaxGFloat](c *Converter, format string, f F, v T, err error, limits vaxLimits[T]) (T, error) {
+	const (
+		signBit  = 0x8000 // sign bit of the VAX types, with their words swapped
+		reserved = signBit
+	)
+
+	var a Action
+	switch {
+	case errors.Is(err, ErrNotFinite) && math.IsNaN(float64(f)):
+		a = c.nan
+	case errors.Is(err, ErrNotFinite):
+		a = c.inf
+	case errors.Is(err, ErrOverflow):
+		a = c.overflow
+	case err == nil && v == 0 && f != 0:
+		if c.rounding && underflowsUp(float64(f), limits.minValue, c.mode) {
+			v = limits.min
+			if math.Signbit(float64(f)) {
+				v |= signBit
+			}
+		}
+		if a = c.underflow; a == Report {
+			err = &ConversionError{format, true, f, v, ErrUnderflow}
+		}
+	default:
+		return v, err
+	}
+
+	switch a {
+	case Default, Report:
+		return v, err
+	case Ignore:
+		return v, nil
+	case ToZero:
+		return 0, nil
+	case ToNaN:
+		return reserved, nil
+	case ToNearest:
+		if math.Abs(float64(f)) <= limits.minValue/2 {
+			return 0, nil
+		}
+		v = limits.min
+	default: // Saturate
+		v = limits.max
+	}
+
+	if math.Signbit(float64(f)) {
+		v |= signBit
+	}
+	return v, nil
+}
@@ -0,0 +1,178 @@
+package vaxdata
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestConverterFromVax(t *testing.T) {
+	var (
+		pi       = []byte{0x0F, 0xD0, 0x41, 0x49}
+		reserved = []byte{0x00, 0x00, 0x80, 0x00}
+		dirty    = []byte{0x00, 0x01, 0x00, 0x00}
+	)
+
+	var tests = []struct {
+		opts []Option
+		buf  []byte
+		want float32
+		err  error
+	}{
+		{nil, pi, 3.141590, nil},
+		{nil, reserved, 0, ErrReservedOperand},
+		{nil, dirty, 0, nil},
+		{[]Option{OnReservedOperand(ToNaN)}, reserved, float32(math.NaN()), nil},
+		{[]Option{OnReservedOperand(Ignore)}, reserved, 0, nil},
+		{[]Option{OnReservedOperand(Report)}, reserved, 0, ErrReservedOperand},
+		{[]Option{OnDirtyZero(Report)}, dirty, 0, ErrDirtyZero},
+		{[]Option{OnDirtyZero(ToNaN)}, dirty, float32(math.NaN()), nil},
+		{[]Option{OnReservedOperand(ToNaN), OnDirtyZero(ToNaN)}, pi, 3.141590, nil},
+	}
+
+	for _, tt := range tests {
+		c, err := NewConverter(tt.opts...)
+		if err != nil {
+			t.Fatalf("NewConverter raised unexpected error: %q", err)
+		}
+		f, err := c.Float32fromVaxFFloat(tt.buf)
+		if !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+			t.Errorf("Float32fromVaxFFloat(%X) raised %v, want %v", tt.buf, err, tt.err)
+		}
+		if f != tt.want && !(math.IsNaN(float64(f)) && math.IsNaN(float64(tt.want))) {
+			t.Errorf("Float32fromVaxFFloat(%X) == %v, want %v", tt.buf, f, tt.want)
+		}
+	}
+
+	c, _ := NewConverter(OnReservedOperand(ToNaN))
+	if g, err := c.Float64fromVaxGFloat([]byte{0, 0, 0, 0, 0x00, 0x00, 0x80, 0x00}); err != nil || !math.IsNaN(g) {
+		t.Errorf("Float64fromVaxGFloat of a reserved operand == %v, %v, want NaN", g, err)
+	}
+	if d, err := c.Float64fromVaxDFloat([]byte{0, 0, 0, 0, 0x00, 0x00, 0x80, 0x00}); err != nil || !math.IsNaN(d) {
+		t.Errorf("Float64fromVaxDFloat of a reserved operand == %v, %v, want NaN", d, err)
+	}
+}
+
+func TestConverterToVax(t *testing.T) {
+	var tests = []struct {
+		opts []Option
+		f    float64
+		want VaxGFloat
+		err  error
+	}{
+		{nil, math.Pi, 0x2D18544421FB4029, nil},
+		{nil, math.Inf(1), 0x0000000000007FF0, ErrNotFinite},
+		{nil, math.SmallestNonzeroFloat64, 0, nil},
+		{[]Option{OnInf(Saturate)}, math.Inf(-1), 0xFFFFFFFFFFFFFFFF, nil},
+		{[]Option{OnInf(ToZero)}, math.Inf(1), 0, nil},
+		{[]Option{OnInf(Ignore)}, math.Inf(1), 0x0000000000007FF0, nil},
+		{[]Option{OnNaN(ToNaN)}, math.NaN(), 0x0000000000008000, nil},
+		{[]Option{OnNaN(Report)}, math.NaN(), 0x0000000000007FF0, ErrNotFinite},
+		{[]Option{OnUnderflow(Report)}, math.SmallestNonzeroFloat64, 0, ErrUnderflow},
+		{[]Option{OnUnderflow(Report)}, 0, 0, nil},
+		{[]Option{OnUnderflow(ToNearest)}, 0x1p-1024, 0x0000000000000010, nil},
+		{[]Option{OnUnderflow(ToNearest)}, -0x1.8p-1025, 0x0000000000008010, nil},
+		{[]Option{OnUnderflow(ToNearest)}, 0x1p-1025, 0, nil},
+		{[]Option{OnUnderflow(ToNearest)}, 0x1p-1030, 0, nil},
+	}
+
+	for _, tt := range tests {
+		c, err := NewConverter(tt.opts...)
+		if err != nil {
+			t.Fatalf("NewConverter raised unexpected error: %q", err)
+		}
+		v, err := c.VaxGFloatfromFloat64(tt.f)
+		if !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+			t.Errorf("VaxGFloatfromFloat64(%v) raised %v, want %v", tt.f, err, tt.err)
+		}
+		if v != tt.want {
+			t.Errorf("VaxGFloatfromFloat64(%v) == %016X, want %016X", tt.f, uint64(v), uint64(tt.want))
+		}
+	}
+
+	// Subnormal float32's below the smallest F_Float underflow to zero
+	if v, err := VaxFFloatfromFloat32(math.SmallestNonzeroFloat32); err != nil || v != 0 {
+		t.Errorf("VaxFFloatfromFloat32(SmallestNonzeroFloat32) == %08X, %v, want 0", uint32(v), err)
+	}
+
+	c, _ := NewConverter(OnOverflow(Saturate))
+	if v, err := c.VaxFFloatfromFloat32(-math.MaxFloat32); err != nil || v != 0xFFFFFFFF {
+		t.Errorf("VaxFFloatfromFloat32(-MaxFloat32) == %08X, %v, want FFFFFFFF", uint32(v), err)
+	}
+	if v, err := c.VaxDFloatfromFloat64(1e300); err != nil || v != 0xFFFFFFFFFFFF7FFF {
+		t.Errorf("VaxDFloatfromFloat64(1e300) == %016X, %v, want FFFFFFFFFFFF7FFF", uint64(v), err)
+	}
+}
+
+func TestConverterReaderWriter(t *testing.T) {
+	c, _ := NewConverter(OnReservedOperand(ToNaN), OnNaN(ToNaN))
+
+	var buf bytes.Buffer
+	w := c.NewVaxFFloatWriter(&buf)
+	if _, err := w.WriteSlice([]float32{3.141590, float32(math.NaN())}); err != nil {
+		t.Fatalf("WriteSlice raised unexpected error: %q", err)
+	}
+	w.Flush()
+	if want := []byte{0x0F, 0xD0, 0x41, 0x49, 0x00, 0x00, 0x80, 0x00}; !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("VaxFFloatWriter wrote %X, want %X", buf.Bytes(), want)
+	}
+
+	r := c.NewVaxFFloatReader(&buf)
+	if f, err := r.Read(); err != nil || f != 3.141590 {
+		t.Errorf("VaxFFloatReader.Read() == %v, %v, want 3.14159", f, err)
+	}
+	if f, err := r.Read(); err != nil || !math.IsNaN(float64(f)) {
+		t.Errorf("VaxFFloatReader.Read() == %v, %v, want NaN", f, err)
+	}
+}
+
+func TestConverterUnderflow(t *testing.T) {
+	c, _ := NewConverter(OnUnderflow(Report))
+
+	v, err := c.VaxFFloatfromFloat32(0x1p-140)
+	var ce *ConversionError
+	if v != 0 || !errors.As(err, &ce) || ce.Err != ErrUnderflow || ce.Input != float32(0x1p-140) {
+		t.Errorf("VaxFFloatfromFloat32(0x1p-140) == %08X, %v, want underflow of the input", uint32(v), err)
+	}
+
+	// Underflow does not stop the writer, keeping the record layout
+	var buf bytes.Buffer
+	w := c.NewVaxFFloatWriter(&buf)
+	n, err := w.WriteSlice([]float32{0x1p-140, 3.141590, 0x1p-141})
+	w.Flush()
+	if n != 3 || !errors.Is(err, ErrUnderflow) || w.Count() != 3 {
+		t.Errorf("WriteSlice == %d, %v with %d written, want 3, underflow", n, err, w.Count())
+	}
+	if want := []byte{0, 0, 0, 0, 0x0F, 0xD0, 0x41, 0x49, 0, 0, 0, 0}; !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("VaxFFloatWriter wrote %X, want %X", buf.Bytes(), want)
+	}
+
+	c, _ = NewConverter(OnUnderflow(ToNearest))
+	if v, err := c.VaxFFloatfromFloat32(-0x1.4p-129); err != nil || v != 0x00008080 {
+		t.Errorf("VaxFFloatfromFloat32(-0x1.4p-129) == %08X, %v, want 00008080", uint32(v), err)
+	}
+	if v, err := c.VaxDFloatfromFloat64(0x1.000001p-129); err != nil || v != 0x0000000000000080 {
+		t.Errorf("VaxDFloatfromFloat64(0x1.000001p-129) == %016X, %v, want 0000000000000080", uint64(v), err)
+	}
+}
+
+func TestNewConverterErrors(t *testing.T) {
+	var tests = [][]Option{
+		{OnReservedOperand(Saturate)},
+		{OnDirtyZero(Saturate)},
+		{OnUnderflow(Saturate)},
+		{OnInf(Saturate), OnUnderflow(Saturate)},
+		{OnOverflow(Action(-1))},
+		{OnUnderflow(Action(99))},
+		{OnOverflow(ToNearest)},
+		{Rounding(big.RoundingMode(99))},
+	}
+
+	for _, opts := range tests {
+		if _, err := NewConverter(opts...); err == nil {
+			t.Errorf("NewConverter(%v) did not raise an error", opts)
+		}
+	}
+}
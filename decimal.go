@@ -0,0 +1,172 @@
+package vaxdata
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimal represents a scaled decimal value, Value * 10^-Scale, as held by
+// the VAX packed decimal and numeric string formats. The scale is not stored
+// in the data itself and must come from the field's definition. The zero
+// Decimal, with a nil Value, is encoded as zero.
+type Decimal struct {
+	Value *big.Int
+	Scale int
+}
+
+// String returns the Decimal with Scale digits after the decimal point.
+func (d Decimal) String() string {
+	digits := "0"
+	if d.Value != nil {
+		digits = new(big.Int).Abs(d.Value).String()
+	}
+	if d.Scale < 0 {
+		digits += strings.Repeat("0", -d.Scale)
+	} else if d.Scale > 0 {
+		if len(digits) <= d.Scale {
+			digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.Scale] + "." + digits[len(digits)-d.Scale:]
+	}
+
+	if d.negative() {
+		return "-" + digits
+	}
+	return digits
+}
+
+// negative reports whether d is less than zero.
+func (d Decimal) negative() bool {
+	return d.Value != nil && d.Value.Sign() < 0
+}
+
+// DecimalDigitError reports an invalid digit in a VAX decimal field.
+type DecimalDigitError struct {
+	Offset int  // byte offset of the digit within the field
+	Digit  byte // the offending nibble or character
+}
+
+func (e *DecimalDigitError) Error() string {
+	return fmt.Sprintf("invalid decimal digit 0x%02X at offset %d", e.Digit, e.Offset)
+}
+
+// DecimalSignError reports an invalid sign in a VAX decimal field.
+type DecimalSignError struct {
+	Offset int  // byte offset of the sign within the field
+	Sign   byte // the offending nibble or character
+}
+
+func (e *DecimalSignError) Error() string {
+	return fmt.Sprintf("invalid decimal sign 0x%02X at offset %d", e.Sign, e.Offset)
+}
+
+// Packed decimal sign nibbles. VAX instructions accept any of 0xA, 0xC, 0xE
+// or 0xF as positive and 0xB or 0xD as negative, but always produce the
+// preferred 0xC and 0xD.
+const (
+	PackedDecimalPlus  byte = 0xC
+	PackedDecimalMinus byte = 0xD
+)
+
+// decimalDigits returns the digits of |v|, left padded with zeros to n
+// digits, or an error if |v| has more than n digits.
+func decimalDigits(v *big.Int, n int, format string) ([]byte, error) {
+	if v == nil {
+		v = new(big.Int)
+	}
+	digits := []byte(new(big.Int).Abs(v).String())
+	if len(digits) > n {
+		return nil, fmt.Errorf("decimal too large for %d digit VAX %s", n, format)
+	}
+
+	padded := make([]byte, n)
+	for i := range padded[:n-len(digits)] {
+		padded[i] = '0'
+	}
+	copy(padded[n-len(digits):], digits)
+	return padded, nil
+}
+
+// DecodePackedDecimal returns the Decimal held in a VAX packed decimal field
+// with the given scale. A field of n bytes holds 2n-1 digits; a field with an
+// even number of digits has a zero high-order nibble.
+func DecodePackedDecimal(buf []byte, scale int) (Decimal, error) {
+	if len(buf) == 0 {
+		return Decimal{}, errors.New("empty VAX packed decimal")
+	}
+
+	v := new(big.Int)
+	ten := big.NewInt(10)
+	digit := new(big.Int)
+	for i, b := range buf {
+		nibbles := []byte{b >> 4, b & 0x0F}
+		if i == len(buf)-1 {
+			// The low-order nibble of the last byte is the sign
+			nibbles = nibbles[:1]
+		}
+
+		for _, n := range nibbles {
+			if n > 9 {
+				return Decimal{}, &DecimalDigitError{Offset: i, Digit: n}
+			}
+			v.Mul(v, ten).Add(v, digit.SetInt64(int64(n)))
+		}
+	}
+
+	switch sign := buf[len(buf)-1] & 0x0F; sign {
+	case 0xA, 0xC, 0xE, 0xF:
+	case 0xB, 0xD:
+		v.Neg(v)
+	default:
+		return Decimal{}, &DecimalSignError{Offset: len(buf) - 1, Sign: sign}
+	}
+
+	return Decimal{Value: v, Scale: scale}, nil
+}
+
+// PackedDecimalString returns the decimal string held in a VAX packed
+// decimal field with the given scale.
+func PackedDecimalString(buf []byte, scale int) (string, error) {
+	d, err := DecodePackedDecimal(buf, scale)
+	if err != nil {
+		return "", err
+	}
+	return d.String(), nil
+}
+
+// EncodePackedDecimal returns the VAX packed decimal representation of d in a
+// field of the given number of digits, using the preferred sign nibbles. The
+// digits of d.Value are stored as is; d.Scale is implied by the field.
+func EncodePackedDecimal(d Decimal, digits int) ([]byte, error) {
+	if digits < 1 {
+		return nil, errors.New("VAX packed decimal must have at least one digit")
+	}
+
+	// Round up to an odd number of digits so the sign completes the last byte
+	n := digits | 1
+	ascii, err := decimalDigits(d.Value, digits, "packed decimal")
+	if err != nil {
+		return nil, err
+	}
+	if n != digits {
+		ascii = append([]byte{'0'}, ascii...)
+	}
+
+	sign := PackedDecimalPlus
+	if d.negative() {
+		sign = PackedDecimalMinus
+	}
+
+	buf := make([]byte, n/2+1)
+	for i := range buf {
+		hi := ascii[2*i] - '0'
+		lo := sign
+		if 2*i+1 < n {
+			lo = ascii[2*i+1] - '0'
+		}
+		buf[i] = hi<<4 | lo
+	}
+	return buf, nil
+}
@@ -0,0 +1,79 @@
+package vaxdata
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestPackedDecimal(t *testing.T) {
+	cases := []struct {
+		in     []byte
+		digits int
+		scale  int
+		want   string
+	}{
+		{[]byte{0x12, 0x34, 0x5C}, 5, 0, "12345"},
+		{[]byte{0x12, 0x34, 0x5D}, 5, 2, "-123.45"},
+		{[]byte{0x01, 0x23, 0x4C}, 4, 4, "0.1234"},
+		{[]byte{0x00, 0x00, 0x5D}, 5, 3, "-0.005"},
+		{[]byte{0x0C}, 1, 0, "0"},
+		{[]byte{0x7D}, 1, -2, "-700"},
+	}
+	for _, c := range cases {
+		got, err := PackedDecimalString(c.in, c.scale)
+		if err != nil {
+			t.Errorf("PackedDecimalString(%X, %d) raised unexpected error: %q", c.in, c.scale, err)
+		} else if got != c.want {
+			t.Errorf("PackedDecimalString(%X, %d) == %s, want %s", c.in, c.scale, got, c.want)
+		}
+
+		d, _ := DecodePackedDecimal(c.in, c.scale)
+		reverse, err := EncodePackedDecimal(d, c.digits)
+		if err != nil {
+			t.Errorf("EncodePackedDecimal(%v, %d) raised unexpected error: %q", d, c.digits, err)
+		} else if !sliceByteEquals(reverse, c.in) {
+			t.Errorf("EncodePackedDecimal(%v, %d) == %X, want %X", d, c.digits, reverse, c.in)
+		}
+	}
+
+	// Alternate sign nibbles are accepted but never produced
+	if d, err := DecodePackedDecimal([]byte{0x12, 0x3B}, 0); err != nil || d.Value.Int64() != -123 {
+		t.Errorf("DecodePackedDecimal(123B) == %v, %v, want -123", d, err)
+	}
+	if d, err := DecodePackedDecimal([]byte{0x12, 0x3F}, 0); err != nil || d.Value.Int64() != 123 {
+		t.Errorf("DecodePackedDecimal(123F) == %v, %v, want 123", d, err)
+	}
+
+	if _, err := EncodePackedDecimal(Decimal{Value: big.NewInt(123456)}, 5); err == nil {
+		t.Errorf("EncodePackedDecimal(123456, 5) did not raise an error")
+	}
+
+	// The zero Decimal has a nil Value
+	if b, err := EncodePackedDecimal(Decimal{}, 3); err != nil || !bytes.Equal(b, []byte{0x00, 0x0C}) {
+		t.Errorf("EncodePackedDecimal(Decimal{}, 3) == %X, %v, want 000C", b, err)
+	}
+	if s := (Decimal{}).String(); s != "0" {
+		t.Errorf("Decimal{}.String() == %q, want %q", s, "0")
+	}
+	if s := (Decimal{Scale: 2}).String(); s != "0.00" {
+		t.Errorf("Decimal{Scale: 2}.String() == %q, want %q", s, "0.00")
+	}
+}
+
+func TestPackedDecimalErrors(t *testing.T) {
+	var digitErr *DecimalDigitError
+	if _, err := DecodePackedDecimal([]byte{0x1A, 0x3C}, 0); !errors.As(err, &digitErr) {
+		t.Errorf("DecodePackedDecimal(1A3C) == %v, want *DecimalDigitError", err)
+	} else if digitErr.Offset != 0 || digitErr.Digit != 0xA {
+		t.Errorf("DecodePackedDecimal(1A3C) == %+v", digitErr)
+	}
+
+	var signErr *DecimalSignError
+	if _, err := DecodePackedDecimal([]byte{0x12, 0x34}, 0); !errors.As(err, &signErr) {
+		t.Errorf("DecodePackedDecimal(1234) == %v, want *DecimalSignError", err)
+	} else if signErr.Offset != 1 || signErr.Sign != 0x4 {
+		t.Errorf("DecodePackedDecimal(1234) == %+v", signErr)
+	}
+}
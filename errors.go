@@ -0,0 +1,63 @@
+package vaxdata
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Errors from converting floating point values, wrapped in a
+// *ConversionError. Test for them with errors.Is.
+var (
+	// ErrReservedOperand reports a VAX reserved operand [s=1, e=0], which
+	// has no value.
+	ErrReservedOperand = errors.New("VAX reserved operand fault")
+
+	// ErrOverflow reports a value too large for the format converted to.
+	ErrOverflow = errors.New("overflow")
+
+	// ErrUnderflow reports a non-zero value too small for the format
+	// converted to. Conversions to VAX formats underflow silently.
+	ErrUnderflow = errors.New("underflow")
+
+	// ErrNotFinite reports an Infinity or NaN, which VAX formats lack.
+	ErrNotFinite = errors.New("no VAX equivalent for +-Infinity and +-NaN")
+
+	// ErrDirtyZero reports a VAX dirty zero [s=e=0, f<>0]. Only a Converter
+	// set to Report them returns it.
+	ErrDirtyZero = errors.New("VAX dirty zero")
+)
+
+// ConversionError reports a floating point value that could not be
+// converted to or from a VAX format, and the fixup value returned instead.
+type ConversionError struct {
+	Format string // VAX format, such as "F_Float"
+	ToVAX  bool   // true if converting to Format, false if from it
+	Input  any    // value converted: a VAX type, float32, float64 or *big.Float
+	Fixup  any    // value returned in place of the result
+	Err    error  // ErrReservedOperand, ErrOverflow, ErrNotFinite, etc.
+}
+
+func (e *ConversionError) Error() string {
+	if e.ToVAX {
+		return fmt.Sprintf("%s to %s: %v", nativeFormat(e.Input), e.Format, e.Err)
+	}
+	return fmt.Sprintf("%s to %s: %v", e.Format, nativeFormat(e.Fixup), e.Err)
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// nativeFormat names the format of a Go floating point value.
+func nativeFormat(x any) string {
+	switch x.(type) {
+	case float32:
+		return "S_Float"
+	case float64:
+		return "T_Float"
+	case *big.Float:
+		return "big.Float"
+	}
+	return fmt.Sprintf("%T", x)
+}
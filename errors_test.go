@@ -0,0 +1,104 @@
+package vaxdata
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestConversionError(t *testing.T) {
+	hmax := VaxHFloat{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F, 0xFF}
+	hmin := VaxHFloat{14: 0x00, 15: 0x01}
+
+	var tests = []struct {
+		conv  func() error
+		want  ConversionError
+		error string
+	}{
+		{
+			func() error { _, err := Float32fromVaxFFloat([]byte{0x00, 0x00, 0x80, 0x00}); return err },
+			ConversionError{"F_Float", false, VaxFFloat(0x00008000), float32(0), ErrReservedOperand},
+			"F_Float to S_Float: VAX reserved operand fault",
+		},
+		{
+			func() error { _, err := Float64fromVaxDFloat([]byte{0, 0, 0, 0, 0x00, 0x00, 0x80, 0x00}); return err },
+			ConversionError{"D_Float", false, VaxDFloat(0x0000000000008000), float64(0), ErrReservedOperand},
+			"D_Float to T_Float: VAX reserved operand fault",
+		},
+		{
+			func() error { _, err := Float64fromVaxGFloat([]byte{0, 0, 0, 0, 0x00, 0x00, 0x80, 0x00}); return err },
+			ConversionError{"G_Float", false, VaxGFloat(0x0000000000008000), float64(0), ErrReservedOperand},
+			"G_Float to T_Float: VAX reserved operand fault",
+		},
+		{
+			func() error { _, err := VaxFFloatfromFloat32(float32(math.Inf(-1))); return err },
+			ConversionError{"F_Float", true, float32(math.Inf(-1)), VaxFFloat(0x0000FF80), ErrNotFinite},
+			"S_Float to F_Float: no VAX equivalent for +-Infinity and +-NaN",
+		},
+		{
+			func() error { _, err := VaxFFloatfromFloat32(math.MaxFloat32); return err },
+			ConversionError{"F_Float", true, float32(math.MaxFloat32), VaxFFloat(0xFFFF7FFF), ErrOverflow},
+			"S_Float to F_Float: overflow",
+		},
+		{
+			func() error { _, err := VaxDFloatfromFloat64(1e300); return err },
+			ConversionError{"D_Float", true, 1e300, VaxDFloat(0xFFFFFFFFFFFF7FFF), ErrOverflow},
+			"T_Float to D_Float: overflow",
+		},
+		{
+			func() error { _, err := VaxGFloatfromFloat64(math.NaN()); return err },
+			ConversionError{"G_Float", true, nil, VaxGFloat(0x0000000000007FF0), ErrNotFinite},
+			"T_Float to G_Float: no VAX equivalent for +-Infinity and +-NaN",
+		},
+		{
+			func() error { _, _, err := Float64fromVaxHFloat(hmax[:]); return err },
+			ConversionError{"H_Float", false, hmax, math.Inf(1), ErrOverflow},
+			"H_Float to T_Float: overflow",
+		},
+		{
+			func() error { _, _, err := Float64fromVaxHFloat(hmin[:]); return err },
+			ConversionError{"H_Float", false, hmin, float64(0), ErrUnderflow},
+			"H_Float to T_Float: underflow",
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.conv()
+		if !errors.Is(err, tt.want.Err) {
+			t.Errorf("%s: errors.Is(%v, %v) == false", tt.error, err, tt.want.Err)
+		}
+		var ce *ConversionError
+		if !errors.As(err, &ce) {
+			t.Errorf("%s: errors.As(%v) found no *ConversionError", tt.error, err)
+			continue
+		}
+		got := *ce
+		if tt.want.Input == nil {
+			// NaN does not compare equal to itself
+			got.Input = nil
+		}
+		if got != tt.want {
+			t.Errorf("%s: ConversionError == %+v, want %+v", tt.error, got, tt.want)
+		}
+		if err.Error() != tt.error {
+			t.Errorf("ConversionError.Error() == %q, want %q", err, tt.error)
+		}
+	}
+
+	hres := VaxHFloat{14: 0x80}
+	_, err := BigFloatfromVaxHFloat(hres[:])
+	if !errors.Is(err, ErrReservedOperand) || err.Error() != "H_Float to big.Float: VAX reserved operand fault" {
+		t.Errorf("BigFloatfromVaxHFloat of a reserved operand == %v", err)
+	}
+	_, err = VaxHFloatfromBigFloat(new(big.Float).SetInf(false))
+	if !errors.Is(err, ErrNotFinite) || err.Error() != "big.Float to H_Float: no VAX equivalent for +-Infinity and +-NaN" {
+		t.Errorf("VaxHFloatfromBigFloat(+Inf) == %v", err)
+	}
+
+	// Errors from each part of a complex value wrap the same sentinels
+	_, err = Complex64fromVaxFComplex([]byte{0, 0, 0x80, 0, 0x0F, 0xD0, 0x41, 0x49})
+	if !errors.Is(err, ErrReservedOperand) {
+		t.Errorf("Complex64fromVaxFComplex of a reserved operand == %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package vaxdata
+
+import "fmt"
+
+// ReadFault describes a value that a reader created by a Converter could not
+// convert.
+type ReadFault struct {
+	Index  int64  // index of the value, counting from the first read
+	Offset int64  // byte offset of the value, counting from the first read
+	Raw    uint64 // bytes of the value as stored on disk, read big-endian
+	Err    error  // the *ConversionError raised
+}
+
+// Decision tells a reader what to do with a value it could not convert.
+type Decision int
+
+const (
+	// Abort returns the fixup value and error, as without an ErrorHandler.
+	Abort Decision = iota
+
+	// Substitute returns the value given by the ErrorHandler without an
+	// error.
+	Substitute
+
+	// Skip drops the value and reads the next.
+	Skip
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Abort:
+		return "Abort"
+	case Substitute:
+		return "Substitute"
+	case Skip:
+		return "Skip"
+	}
+	return fmt.Sprintf("Decision(%d)", int(d))
+}
+
+// ErrorHandler decides what a reader does with a value it could not
+// convert. The float64 is the value to Substitute, converted to float32 by
+// a VaxFFloatReader.
+type ErrorHandler func(fault ReadFault) (Decision, float64)
+
+// readFault calls the ErrorHandler of c for the value at index, of size
+// bytes.
+func (c *Converter) readFault(index int64, size int, raw uint64, err error) (Decision, float64) {
+	return c.onError(ReadFault{index, index * int64(size), raw, err})
+}
@@ -0,0 +1,85 @@
+package vaxdata
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestReadErrorHandler(t *testing.T) {
+	var (
+		pi       = []byte{0x0F, 0xD0, 0x41, 0x49}
+		reserved = []byte{0x00, 0x00, 0x80, 0x00}
+		stream   = bytes.Join([][]byte{pi, reserved, pi, reserved, pi}, nil)
+	)
+
+	var faults []ReadFault
+	c, _ := NewConverter(OnReadError(func(fault ReadFault) (Decision, float64) {
+		faults = append(faults, fault)
+		if fault.Index == 1 {
+			return Substitute, math.NaN()
+		}
+		return Abort, 0
+	}))
+
+	r := c.NewVaxFFloatReader(bytes.NewReader(stream))
+	var got []float32
+	var err error
+	for {
+		var f float32
+		if f, err = r.Read(); err != nil {
+			break
+		}
+		got = append(got, f)
+	}
+
+	if len(got) != 3 || got[0] != 3.141590 || !math.IsNaN(float64(got[1])) || got[2] != 3.141590 {
+		t.Errorf("VaxFFloatReader.Read() read %v before aborting, want [3.14159 NaN 3.14159]", got)
+	}
+	if !errors.Is(err, ErrReservedOperand) {
+		t.Errorf("VaxFFloatReader.Read() raised %v, want %v", err, ErrReservedOperand)
+	}
+	if len(faults) != 2 || faults[0].Index != 1 || faults[0].Offset != 4 || faults[1].Index != 3 || faults[1].Offset != 12 {
+		t.Fatalf("ErrorHandler called with %+v, want faults at 1 and 3", faults)
+	}
+	if faults[0].Raw != 0x00008000 || !errors.Is(faults[0].Err, ErrReservedOperand) {
+		t.Errorf("ReadFault == %+v, want the raw reserved operand", faults[0])
+	}
+
+	// The reader carries on after an abort
+	if f, err := r.Read(); err != nil || f != 3.141590 || r.Count() != 5 {
+		t.Errorf("VaxFFloatReader.Read() == %v, %v after %d values, want 3.14159 after 5", f, err, r.Count())
+	}
+}
+
+func TestReadErrorHandlerSkip(t *testing.T) {
+	skip := func(ReadFault) (Decision, float64) { return Skip, 0 }
+	c, _ := NewConverter(OnDirtyZero(Report), OnReadError(skip))
+
+	var stream []byte
+	stream = append(stream, 0x68, 0xC0, 0xA2, 0x21, 0x0F, 0xDA, 0x41, 0x49) // pi
+	stream = append(stream, 0, 0, 0, 0, 0x00, 0x01, 0x00, 0x00)             // dirty zero
+	stream = append(stream, 0, 0, 0, 0, 0x00, 0x00, 0x80, 0x00)             // reserved operand
+	stream = append(stream, 0x68, 0xC0, 0xA2, 0x21, 0x0F, 0xDA, 0x41, 0x49) // pi
+
+	r := c.NewVaxDFloatReader(bytes.NewReader(stream))
+	for i := range 2 {
+		if f, err := r.Read(); err != nil || f != math.Pi {
+			t.Errorf("VaxDFloatReader.Read() %d == %v, %v, want %v", i, f, err, math.Pi)
+		}
+	}
+	if _, err := r.Read(); err != io.EOF || r.Count() != 4 {
+		t.Errorf("VaxDFloatReader.Read() raised %v after %d values, want EOF after 4", err, r.Count())
+	}
+
+	// Without a handler the reader returns the error for each value
+	r = NewVaxDFloatReader(bytes.NewReader(stream[16:]))
+	if _, err := r.Read(); !errors.Is(err, ErrReservedOperand) {
+		t.Errorf("VaxDFloatReader.Read() raised %v, want %v", err, ErrReservedOperand)
+	}
+	if f, err := r.Read(); err != nil || f != math.Pi {
+		t.Errorf("VaxDFloatReader.Read() == %v, %v, want %v", f, err, math.Pi)
+	}
+}
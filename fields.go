@@ -0,0 +1,163 @@
+package vaxdata
+
+import "fmt"
+
+// The bit field accessors apply the mask constants to the words of a value in
+// the order the constants expect, so that callers need not swap them. The
+// exponent is biased, as stored; UnbiasedExponent removes the bias, giving
+// the value (-1)^s * 2^(e-bias) * 0.1f with the hidden bit restored.
+
+// Class is the kind of value held by a VAX floating point number.
+type Class int
+
+const (
+	ClassNormal          Class = iota // normalized, non-zero [e<>0]
+	ClassZero                         // true zero [s=e=f=0]
+	ClassDirtyZero                    // zero with a non-zero fraction [s=e=0, f<>0]
+	ClassReservedOperand              // reserved operand [s=1, e=0]
+)
+
+func (c Class) String() string {
+	switch c {
+	case ClassNormal:
+		return "normal"
+	case ClassZero:
+		return "zero"
+	case ClassDirtyZero:
+		return "dirty zero"
+	case ClassReservedOperand:
+		return "reserved operand"
+	}
+	return fmt.Sprintf("Class(%d)", int(c))
+}
+
+// classify returns the Class of a value from its bit fields.
+func classify(sign uint, exp int, nonzero bool) Class {
+	switch {
+	case exp != 0:
+		return ClassNormal
+	case sign != 0:
+		return ClassReservedOperand
+	case nonzero:
+		return ClassDirtyZero
+	}
+	return ClassZero
+}
+
+// VaxFFloatfromFields returns the F_Float with the given sign bit, biased
+// exponent and fraction, which must fit their fields.
+func VaxFFloatfromFields(sign uint, exp int, frac uint32) (VaxFFloat, error) {
+	if sign > 1 || exp < 0 || exp > int(VaxFExponentMask>>VaxFMantissaSize) || frac > VaxFMantissaMask {
+		return 0, fmt.Errorf("s=%d e=%d f=0x%X do not fit a VAX F_Float", sign, exp, frac)
+	}
+	part1 := uint32(sign)<<31 | uint32(exp)<<VaxFMantissaSize | frac
+	return VaxFFloat(uint32FromVax(part1)), nil
+}
+
+// Sign returns the sign bit of the F_Float, 1 if it is negative.
+func (v VaxFFloat) Sign() uint { return uint(uint32FromVax(uint32(v)) >> 31) }
+
+// Exponent returns the biased exponent of the F_Float.
+func (v VaxFFloat) Exponent() int {
+	return int((uint32FromVax(uint32(v)) & VaxFExponentMask) >> VaxFMantissaSize)
+}
+
+// UnbiasedExponent returns the exponent of the F_Float less its bias.
+func (v VaxFFloat) UnbiasedExponent() int { return v.Exponent() - int(VaxFExponentBias) }
+
+// Fraction returns the fraction of the F_Float, without the hidden bit.
+func (v VaxFFloat) Fraction() uint32 { return uint32FromVax(uint32(v)) & VaxFMantissaMask }
+
+// IsZero reports whether the F_Float is a true or dirty zero.
+func (v VaxFFloat) IsZero() bool { return v.Exponent() == 0 && v.Sign() == 0 }
+
+// IsDirtyZero reports whether the F_Float is a zero with a non-zero fraction.
+func (v VaxFFloat) IsDirtyZero() bool { return v.Classify() == ClassDirtyZero }
+
+// IsReservedOperand reports whether the F_Float is a reserved operand.
+func (v VaxFFloat) IsReservedOperand() bool { return v.Classify() == ClassReservedOperand }
+
+// Classify returns the Class of the F_Float.
+func (v VaxFFloat) Classify() Class { return classify(v.Sign(), v.Exponent(), v.Fraction() != 0) }
+
+// VaxDFloatfromFields returns the D_Float with the given sign bit, biased
+// exponent and fraction, which must fit their fields.
+func VaxDFloatfromFields(sign uint, exp int, frac uint64) (VaxDFloat, error) {
+	if sign > 1 || exp < 0 || exp > int(VaxDExponentMask>>VaxDMantissaSize) || frac>>32 > uint64(VaxDMantissaMask) {
+		return 0, fmt.Errorf("s=%d e=%d f=0x%X do not fit a VAX D_Float", sign, exp, frac)
+	}
+	part1 := uint32(sign)<<31 | uint32(exp)<<VaxDMantissaSize | uint32(frac>>32)
+	part2 := uint32(frac)
+	return VaxDFloat(uint64(uint32FromVax(part2))<<32 | uint64(uint32FromVax(part1))), nil
+}
+
+// Sign returns the sign bit of the D_Float, 1 if it is negative.
+func (v VaxDFloat) Sign() uint { return uint(uint32FromVax(uint32(v)) >> 31) }
+
+// Exponent returns the biased exponent of the D_Float.
+func (v VaxDFloat) Exponent() int {
+	return int((uint32FromVax(uint32(v)) & VaxDExponentMask) >> VaxDMantissaSize)
+}
+
+// UnbiasedExponent returns the exponent of the D_Float less its bias.
+func (v VaxDFloat) UnbiasedExponent() int { return v.Exponent() - int(VaxDExponentBias) }
+
+// Fraction returns the fraction of the D_Float, without the hidden bit.
+func (v VaxDFloat) Fraction() uint64 {
+	part1 := uint32FromVax(uint32(v)) & VaxDMantissaMask
+	part2 := uint32FromVax(uint32(v >> 32))
+	return uint64(part1)<<32 | uint64(part2)
+}
+
+// IsZero reports whether the D_Float is a true or dirty zero.
+func (v VaxDFloat) IsZero() bool { return v.Exponent() == 0 && v.Sign() == 0 }
+
+// IsDirtyZero reports whether the D_Float is a zero with a non-zero fraction.
+func (v VaxDFloat) IsDirtyZero() bool { return v.Classify() == ClassDirtyZero }
+
+// IsReservedOperand reports whether the D_Float is a reserved operand.
+func (v VaxDFloat) IsReservedOperand() bool { return v.Classify() == ClassReservedOperand }
+
+// Classify returns the Class of the D_Float.
+func (v VaxDFloat) Classify() Class { return classify(v.Sign(), v.Exponent(), v.Fraction() != 0) }
+
+// VaxGFloatfromFields returns the G_Float with the given sign bit, biased
+// exponent and fraction, which must fit their fields.
+func VaxGFloatfromFields(sign uint, exp int, frac uint64) (VaxGFloat, error) {
+	if sign > 1 || exp < 0 || exp > int(VaxGExponentMask>>VaxGMantissaSize) || frac>>32 > uint64(VaxGMantissaMask) {
+		return 0, fmt.Errorf("s=%d e=%d f=0x%X do not fit a VAX G_Float", sign, exp, frac)
+	}
+	part1 := uint32(sign)<<31 | uint32(exp)<<VaxGMantissaSize | uint32(frac>>32)
+	part2 := uint32(frac)
+	return VaxGFloat(uint64(uint32FromVax(part2))<<32 | uint64(uint32FromVax(part1))), nil
+}
+
+// Sign returns the sign bit of the G_Float, 1 if it is negative.
+func (v VaxGFloat) Sign() uint { return uint(uint32FromVax(uint32(v)) >> 31) }
+
+// Exponent returns the biased exponent of the G_Float.
+func (v VaxGFloat) Exponent() int {
+	return int((uint32FromVax(uint32(v)) & VaxGExponentMask) >> VaxGMantissaSize)
+}
+
+// UnbiasedExponent returns the exponent of the G_Float less its bias.
+func (v VaxGFloat) UnbiasedExponent() int { return v.Exponent() - int(VaxGExponentBias) }
+
+// Fraction returns the fraction of the G_Float, without the hidden bit.
+func (v VaxGFloat) Fraction() uint64 {
+	part1 := uint32FromVax(uint32(v)) & VaxGMantissaMask
+	part2 := uint32FromVax(uint32(v >> 32))
+	return uint64(part1)<<32 | uint64(part2)
+}
+
+// IsZero reports whether the G_Float is a true or dirty zero.
+func (v VaxGFloat) IsZero() bool { return v.Exponent() == 0 && v.Sign() == 0 }
+
+// IsDirtyZero reports whether the G_Float is a zero with a non-zero fraction.
+func (v VaxGFloat) IsDirtyZero() bool { return v.Classify() == ClassDirtyZero }
+
+// IsReservedOperand reports whether the G_Float is a reserved operand.
+func (v VaxGFloat) IsReservedOperand() bool { return v.Classify() == ClassReservedOperand }
+
+// Classify returns the Class of the G_Float.
+func (v VaxGFloat) Classify() Class { return classify(v.Sign(), v.Exponent(), v.Fraction() != 0) }
@@ -0,0 +1,87 @@
+package vaxdata
+
+import "testing"
+
+func TestVaxFloatFields(t *testing.T) {
+	type fields interface {
+		Sign() uint
+		Exponent() int
+		UnbiasedExponent() int
+		IsZero() bool
+		Classify() Class
+	}
+
+	var tests = []struct {
+		v     fields
+		sign  uint
+		exp   int
+		frac  uint64
+		class Class
+	}{
+		{VaxFFloat(0x0FD04149), 0, 130, 0x490FD0, ClassNormal},
+		{VaxFFloat(0x0FD0C149), 1, 130, 0x490FD0, ClassNormal},
+		{VaxFFloat(0x00000000), 0, 0, 0, ClassZero},
+		{VaxFFloat(0x00010000), 0, 0, 1, ClassDirtyZero},
+		{VaxFFloat(0x00008000), 1, 0, 0, ClassReservedOperand},
+		{VaxDFloat(0x68C0A2210FDA4149), 0, 130, 0x490FDAA22168C0, ClassNormal},
+		{VaxDFloat(0x0001000000000000), 0, 0, 1, ClassDirtyZero},
+		{VaxGFloat(0x2D18544421FB4029), 0, 1026, 0x921FB54442D18, ClassNormal},
+		{VaxGFloat(0x000000000000FFFF), 1, 2047, 0xF000000000000, ClassNormal},
+		{VaxGFloat(0x0000000000008000), 1, 0, 0, ClassReservedOperand},
+	}
+
+	for _, tt := range tests {
+		var (
+			frac uint64
+			back fields
+			err  error
+		)
+		switch v := tt.v.(type) {
+		case VaxFFloat:
+			frac = uint64(v.Fraction())
+			back, err = VaxFFloatfromFields(tt.sign, tt.exp, uint32(tt.frac))
+			if v.IsDirtyZero() != (tt.class == ClassDirtyZero) || v.IsReservedOperand() != (tt.class == ClassReservedOperand) {
+				t.Errorf("%08X IsDirtyZero, IsReservedOperand == %v, %v for %v", uint32(v), v.IsDirtyZero(), v.IsReservedOperand(), tt.class)
+			}
+		case VaxDFloat:
+			frac = v.Fraction()
+			back, err = VaxDFloatfromFields(tt.sign, tt.exp, tt.frac)
+		case VaxGFloat:
+			frac = v.Fraction()
+			back, err = VaxGFloatfromFields(tt.sign, tt.exp, tt.frac)
+		}
+
+		if s, e, c := tt.v.Sign(), tt.v.Exponent(), tt.v.Classify(); s != tt.sign || e != tt.exp || frac != tt.frac || c != tt.class {
+			t.Errorf("%T(%x) fields == s=%d e=%d f=0x%X %v, want s=%d e=%d f=0x%X %v",
+				tt.v, tt.v, s, e, frac, c, tt.sign, tt.exp, tt.frac, tt.class)
+		}
+		if z := tt.v.IsZero(); z != (tt.class == ClassZero || tt.class == ClassDirtyZero) {
+			t.Errorf("%T(%x).IsZero() == %v for %v", tt.v, tt.v, z, tt.class)
+		}
+		if err != nil || back != tt.v {
+			t.Errorf("%T from fields s=%d e=%d f=0x%X == %x, %v, want %x", tt.v, tt.sign, tt.exp, tt.frac, back, err, tt.v)
+		}
+	}
+
+	if e := VaxFFloat(0x0FD04149).UnbiasedExponent(); e != 2 {
+		t.Errorf("VaxFFloat(0FD04149).UnbiasedExponent() == %d, want 2", e)
+	}
+	if e := VaxGFloat(0x2D18544421FB4029).UnbiasedExponent(); e != 2 {
+		t.Errorf("VaxGFloat(2D18544421FB4029).UnbiasedExponent() == %d, want 2", e)
+	}
+}
+
+func TestVaxFloatfromFieldsErrors(t *testing.T) {
+	if _, err := VaxFFloatfromFields(2, 1, 0); err == nil {
+		t.Errorf("VaxFFloatfromFields with s=2 did not raise an error")
+	}
+	if _, err := VaxFFloatfromFields(0, 256, 0); err == nil {
+		t.Errorf("VaxFFloatfromFields with e=256 did not raise an error")
+	}
+	if _, err := VaxDFloatfromFields(0, 1, 1<<55); err == nil {
+		t.Errorf("VaxDFloatfromFields with a 56-bit fraction did not raise an error")
+	}
+	if _, err := VaxGFloatfromFields(0, 2048, 0); err == nil {
+		t.Errorf("VaxGFloatfromFields with e=2048 did not raise an error")
+	}
+}
@@ -1,12 +1,78 @@
 package vaxdata
 
 import (
+	"bufio"
 	"encoding/binary"
 	"errors"
 	"io"
 	"math"
+	"math/big"
 )
 
+// VaxFFloatWriter writes float32 values as F_Float's to the underlying
+// io.Writer. Output is buffered; call Flush once all values are written.
+type VaxFFloatWriter struct {
+	w   *bufio.Writer
+	buf []byte
+	n   int64
+	c   Converter
+}
+
+// NewVaxFFloatWriter creates a new VaxFFloatWriter. VaxFFloatWriter.Write
+// writes a float32 as a F_Float to the underlying io.Writer.
+func NewVaxFFloatWriter(w io.Writer) *VaxFFloatWriter {
+	vaxout := new(VaxFFloatWriter)
+	(*vaxout).w = bufio.NewWriter(w)
+	(*vaxout).buf = make([]byte, 4)
+	return vaxout
+}
+
+// Write takes a float32 and writes a F_Float. Values that cannot be
+// converted are not written, except that underflow reported by a Converter
+// is written and its error returned.
+func (vaxout *VaxFFloatWriter) Write(f float32) error {
+	v, err := vaxout.c.VaxFFloatfromFloat32(f)
+	if err != nil && !errors.Is(err, ErrUnderflow) {
+		return err
+	}
+
+	binary.BigEndian.PutUint32(vaxout.buf, uint32(v))
+	if _, err := vaxout.w.Write(vaxout.buf); err != nil {
+		return err
+	}
+	vaxout.n++
+	return err
+}
+
+// WriteSlice writes each float32 in fs as a F_Float, stopping at the first
+// error, and returns the number of values written. Underflow reported by a
+// Converter does not stop it; the first such error is returned once every
+// value is written.
+func (vaxout *VaxFFloatWriter) WriteSlice(fs []float32) (int, error) {
+	var underflow error
+	for i, f := range fs {
+		if err := vaxout.Write(f); err != nil {
+			if !errors.Is(err, ErrUnderflow) {
+				return i, err
+			}
+			if underflow == nil {
+				underflow = err
+			}
+		}
+	}
+	return len(fs), underflow
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (vaxout *VaxFFloatWriter) Flush() error {
+	return vaxout.w.Flush()
+}
+
+// Count returns the number of values written.
+func (vaxout *VaxFFloatWriter) Count() int64 {
+	return vaxout.n
+}
+
 // WriteFFloat takes a float32 and writes an F_Float to the io.Writer.
 func WriteFFloat(w io.Writer, f float32) error {
 	v, err := VaxFFloatfromFloat32(f)
@@ -37,12 +103,12 @@ func VaxFFloatfromFloat32(f float32) (VaxFFloat, error) {
 		result = 0
 	} else if e := (ieeepart1 & IeeeSExponentMask); e == IeeeSExponentMask {
 		// VAX's have no equivalents for IEEE +-Infinity and +-NaN [e=all-1's]
-		err = errors.New("no VAX equivalent for IEEE +-Infinity and +-NaN")
+		err = ErrNotFinite
 
 		// Fixup to VAX +-extrema [e=all-1's] with zero mantissa [m=0]
 		result = (ieeepart1 & SignBit) | VaxFExponentMask
 	} else {
-		e >>= MantissaSize              // Obtain the biased IEEE exponent
+		e := int32(e >> MantissaSize)   // Obtain the biased IEEE exponent
 		m := (ieeepart1 & MantissaMask) // Obtain the IEEE mantissa
 
 		// Denormalized? [e=0, m<>0]
@@ -57,19 +123,144 @@ func VaxFFloatfromFloat32(f float32) (VaxFFloat, error) {
 			m &= MantissaMask
 		}
 
-		if e += ExponentAdjustment; e <= 0 {
+		if e += int32(ExponentAdjustment); e <= 0 {
 			result = 0 // Silent underflow
-		} else if e > (2*VaxFExponentBias - 1) {
+		} else if e > int32(2*VaxFExponentBias-1) {
 			// Overflow; fixup to VAX +-extrema [e=m=all-1's]
-			err = errors.New("IEEE S_Float too large for VAX F_Float")
+			err = ErrOverflow
 			result = (ieeepart1 & SignBit) | ^SignBit
 		} else {
 			// VAX normalized form [e>0] (both mantissas are 23 bits)
-			result = (ieeepart1 & SignBit) | (e << MantissaSize) | m
+			result = (ieeepart1 & SignBit) | (uint32(e) << MantissaSize) | m
+		}
+	}
+
+	v := VaxFFloat(uint32FromVax(result))
+	if err != nil {
+		return v, &ConversionError{"F_Float", true, f, v, err}
+	}
+	return v, nil
+}
+
+// VaxFFloatfromFloat64 returns the F_Float nearest to a float64, ties to
+// even. Unlike converting through float32, it rounds once, and keeps the
+// bits of values from 2**-128 to 2**-126, which are float32 subnormals.
+func VaxFFloatfromFloat64(f float64) (VaxFFloat, error) {
+	return vaxFFloatfromFloat64(f, big.ToNearestEven)
+}
+
+// vaxFFloatfromFloat64 is VaxFFloatfromFloat64, rounding by mode.
+func vaxFFloatfromFloat64(f float64, mode big.RoundingMode) (VaxFFloat, error) {
+	const (
+		emax  = int(VaxFExponentMask >> VaxFMantissaSize)
+		mbits = 24 // F_Float mantissa bits, including the hidden bit
+	)
+
+	var sign uint
+	if math.Signbit(f) {
+		sign = 1
+	}
+
+	var (
+		v   VaxFFloat
+		err error
+	)
+	switch {
+	case f == 0:
+		return 0, nil
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		// Fixup to VAX +-extrema [e=all-1's] with zero mantissa [m=0]
+		v, _ = VaxFFloatfromFields(sign, emax, 0)
+		err = ErrNotFinite
+	default:
+		// |f| is 0.m * 2**exp, as is an F_Float
+		frac, exp := math.Frexp(math.Abs(f))
+		m := roundShift(uint64(math.Ldexp(frac, 53)), 53-mbits, sign != 0, mode)
+		if m == 1<<mbits {
+			// Rounded up into the next binade
+			m >>= 1
+			exp++
+		}
+
+		if e := exp + int(VaxFExponentBias); e <= 0 {
+			return 0, nil // Silent underflow
+		} else if e > emax {
+			// Overflow; fixup to VAX +-extrema [e=m=all-1's]
+			v, _ = VaxFFloatfromFields(sign, emax, VaxFMantissaMask)
+			err = ErrOverflow
+		} else {
+			v, _ = VaxFFloatfromFields(sign, e, uint32(m)&VaxFMantissaMask)
+		}
+	}
+
+	if err != nil {
+		return v, &ConversionError{"F_Float", true, f, v, err}
+	}
+	return v, nil
+}
+
+// VaxGFloatWriter writes float64 values as G_Float's to the underlying
+// io.Writer. Output is buffered; call Flush once all values are written.
+type VaxGFloatWriter struct {
+	w   *bufio.Writer
+	buf []byte
+	n   int64
+	c   Converter
+}
+
+// NewVaxGFloatWriter creates a new VaxGFloatWriter. VaxGFloatWriter.Write
+// writes a float64 as a G_Float to the underlying io.Writer.
+func NewVaxGFloatWriter(w io.Writer) *VaxGFloatWriter {
+	vaxout := new(VaxGFloatWriter)
+	(*vaxout).w = bufio.NewWriter(w)
+	(*vaxout).buf = make([]byte, 8)
+	return vaxout
+}
+
+// Write takes a float64 and writes a G_Float. Values that cannot be
+// converted are not written, except that underflow reported by a Converter
+// is written and its error returned.
+func (vaxout *VaxGFloatWriter) Write(f float64) error {
+	v, err := vaxout.c.VaxGFloatfromFloat64(f)
+	if err != nil && !errors.Is(err, ErrUnderflow) {
+		return err
+	}
+
+	binary.BigEndian.PutUint64(vaxout.buf, uint64(v))
+	if _, err := vaxout.w.Write(vaxout.buf); err != nil {
+		return err
+	}
+	vaxout.n++
+	return err
+}
+
+// WriteSlice writes each float64 in fs as a G_Float, stopping at the first
+// error, and returns the number of values written. Underflow reported by a
+// Converter does not stop it; the first such error is returned once every
+// value is written.
+func (vaxout *VaxGFloatWriter) WriteSlice(fs []float64) (int, error) {
+	var underflow error
+	for i, f := range fs {
+		if err := vaxout.Write(f); err != nil {
+			if !errors.Is(err, ErrUnderflow) {
+				return i, err
+			}
+			if underflow == nil {
+				underflow = err
+			}
 		}
 	}
+	return len(fs), underflow
+}
 
-	return VaxFFloat(uint32FromVax(result)), err
+// Flush writes any buffered data to the underlying io.Writer.
+func (vaxout *VaxGFloatWriter) Flush() error {
+	return vaxout.w.Flush()
+}
+
+// Count returns the number of values written.
+func (vaxout *VaxGFloatWriter) Count() int64 {
+	return vaxout.n
 }
 
 // WriteGFloat takes a float64 and writes an G_Float to the io.Writer.
@@ -106,13 +297,13 @@ func VaxGFloatfromFloat64(f float64) (VaxGFloat, error) {
 		// vaxpart2 is already zero
 	} else if e := (ieeepart1 & IeeeTExponentMask); e == IeeeTExponentMask {
 		// VAX's have no equivalents for IEEE +-Infinity and +-NaN [e=all-1's]
-		err = errors.New("no VAX equivalent for IEEE +-Infinity and +-NaN")
+		err = ErrNotFinite
 
 		// Fixup to VAX +-extrema [e=all-1's] with zero mantissa [m=0]
 		vaxpart1 = (ieeepart1 & SignBit) | VaxGExponentMask
 		vaxpart2 = 0
 	} else {
-		e >>= MantissaSize            // Obtain the biased IEEE exponent
+		e := int32(e >> MantissaSize) // Obtain the biased IEEE exponent
 		m := ieeepart1 & MantissaMask // Obtain the IEEE mantissa
 
 		// Denormalized? [e=0, m<>0]
@@ -130,22 +321,159 @@ func VaxGFloatfromFloat64(f float64) (VaxGFloat, error) {
 			m &= MantissaMask
 		}
 
-		if e += ExponentAdjustment; e <= 0 {
+		if e += int32(ExponentAdjustment); e <= 0 {
 			vaxpart1 = 0 // Silent underflow
 			vaxpart2 = 0
-		} else if e > (2*VaxGExponentBias - 1) {
-			err = errors.New("IEEE T_Float too large for VAX G_Float")
+		} else if e > int32(2*VaxGExponentBias-1) {
+			err = ErrOverflow
 
 			// Overflow; fixup to VAX +-extrema [e=m=all-1's]
 			vaxpart1 = (ieeepart1 & SignBit) | ^SignBit
 			vaxpart2 = 0xFFFFFFFF
 		} else {
 			// VAX normalized form [e>0] (both mantissas are 52 bits)
-			vaxpart1 = (ieeepart1 & SignBit) | (e << MantissaSize) | m
+			vaxpart1 = (ieeepart1 & SignBit) | (uint32(e) << MantissaSize) | m
 			// vaxpart2 is already correct
 		}
 	}
 
 	result := (uint64(uint32FromVax(vaxpart2)) << 32) | uint64(uint32FromVax(vaxpart1))
-	return VaxGFloat(result), err
+	if err != nil {
+		return VaxGFloat(result), &ConversionError{"G_Float", true, f, VaxGFloat(result), err}
+	}
+	return VaxGFloat(result), nil
+}
+
+// VaxDFloatWriter writes float64 values as D_Float's to the underlying
+// io.Writer. Output is buffered; call Flush once all values are written.
+type VaxDFloatWriter struct {
+	w   *bufio.Writer
+	buf []byte
+	n   int64
+	c   Converter
+}
+
+// NewVaxDFloatWriter creates a new VaxDFloatWriter. VaxDFloatWriter.Write
+// writes a float64 as a D_Float to the underlying io.Writer.
+func NewVaxDFloatWriter(w io.Writer) *VaxDFloatWriter {
+	vaxout := new(VaxDFloatWriter)
+	(*vaxout).w = bufio.NewWriter(w)
+	(*vaxout).buf = make([]byte, 8)
+	return vaxout
+}
+
+// Write takes a float64 and writes a D_Float. Values that cannot be
+// converted are not written, except that underflow reported by a Converter
+// is written and its error returned.
+func (vaxout *VaxDFloatWriter) Write(f float64) error {
+	v, err := vaxout.c.VaxDFloatfromFloat64(f)
+	if err != nil && !errors.Is(err, ErrUnderflow) {
+		return err
+	}
+
+	binary.BigEndian.PutUint64(vaxout.buf, uint64(v))
+	if _, err := vaxout.w.Write(vaxout.buf); err != nil {
+		return err
+	}
+	vaxout.n++
+	return err
+}
+
+// WriteSlice writes each float64 in fs as a D_Float, stopping at the first
+// error, and returns the number of values written. Underflow reported by a
+// Converter does not stop it; the first such error is returned once every
+// value is written.
+func (vaxout *VaxDFloatWriter) WriteSlice(fs []float64) (int, error) {
+	var underflow error
+	for i, f := range fs {
+		if err := vaxout.Write(f); err != nil {
+			if !errors.Is(err, ErrUnderflow) {
+				return i, err
+			}
+			if underflow == nil {
+				underflow = err
+			}
+		}
+	}
+	return len(fs), underflow
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (vaxout *VaxDFloatWriter) Flush() error {
+	return vaxout.w.Flush()
+}
+
+// Count returns the number of values written.
+func (vaxout *VaxDFloatWriter) Count() int64 {
+	return vaxout.n
+}
+
+// WriteDFloat takes a float64 and writes an D_Float to the io.Writer.
+func WriteDFloat(w io.Writer, f float64) error {
+	v, err := VaxDFloatfromFloat64(f)
+	if err != nil {
+		return err
+	}
+
+	return binary.Write(w, binary.BigEndian, uint64(v))
+}
+
+// VaxDFloatfromFloat64 returns the VAX D_Float representation of a float64.
+//
+// D_Float has the same exponent range as F_Float, so float64 values outside
+// of it underflow to zero or overflow to the VAX extrema. Within range the
+// conversion is exact.
+func VaxDFloatfromFloat64(f float64) (VaxDFloat, error) {
+	const (
+		MantissaSize       uint32 = VaxDMantissaSize
+		ExponentAdjustment int32  = int32(1+VaxDExponentBias) - int32(IeeeTExponentBias)
+		ExcessBits         uint32 = (VaxDMantissaSize + 32) - (IeeeTMantissaSize + 32)
+	)
+
+	var (
+		in                 uint64 = math.Float64bits(f)
+		vaxpart1, vaxpart2 uint32
+		err                error
+	)
+
+	ieeepart1 := uint32(in >> 32)
+
+	if (in &^ (uint64(SignBit) << 32)) == 0 {
+		// Set IEEE +-zero [e=m=0] to VAX zero [s=e=m=0]
+		vaxpart1 = 0
+		vaxpart2 = 0
+	} else if e := (ieeepart1 & IeeeTExponentMask); e == IeeeTExponentMask {
+		// VAX's have no equivalents for IEEE +-Infinity and +-NaN [e=all-1's]
+		err = ErrNotFinite
+
+		// Fixup to VAX +-extrema [e=all-1's] with zero mantissa [m=0]
+		vaxpart1 = (ieeepart1 & SignBit) | VaxDExponentMask
+		vaxpart2 = 0
+	} else {
+		// Obtain the biased IEEE exponent. Denormalized values [e=0, m<>0]
+		// are far below the smallest D_Float and simply underflow.
+		ve := int32(e>>IeeeTMantissaSize) + ExponentAdjustment
+		if e == 0 || ve <= 0 {
+			vaxpart1 = 0 // Silent underflow
+			vaxpart2 = 0
+		} else if ve > int32(2*VaxDExponentBias-1) {
+			err = ErrOverflow
+
+			// Overflow; fixup to VAX +-extrema [e=m=all-1's]
+			vaxpart1 = (ieeepart1 & SignBit) | ^SignBit
+			vaxpart2 = 0xFFFFFFFF
+		} else {
+			// VAX normalized form [e>0] (the 52-bit IEEE mantissa is
+			// widened to 55 bits)
+			m := (in & ((1 << (IeeeTMantissaSize + 32)) - 1)) << ExcessBits
+			vaxpart1 = (ieeepart1 & SignBit) | (uint32(ve) << MantissaSize) | uint32(m>>32)
+			vaxpart2 = uint32(m)
+		}
+	}
+
+	result := (uint64(uint32FromVax(vaxpart2)) << 32) | uint64(uint32FromVax(vaxpart1))
+	if err != nil {
+		return VaxDFloat(result), &ConversionError{"D_Float", true, f, VaxDFloat(result), err}
+	}
+	return VaxDFloat(result), nil
 }
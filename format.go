@@ -0,0 +1,137 @@
+package vaxdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// The VAX floating point types format their exact decimal value, as for
+// MarshalText, for the %v, %s and floating point verbs, and the bytes as
+// stored on disk for %x and %X.
+// The %+v verb breaks the value into its sign, biased exponent and fraction:
+//
+//	s=0 e=130 f=0x490FD0 (3.14159)
+//
+// annotating reserved operands and dirty zeros in place of the value. A
+// reserved operand has no value and formats in hex form for the other
+// verbs, as for MarshalText. The remaining verbs, and %#v, format the
+// integer underlying the type.
+
+var (
+	_ fmt.Formatter = VaxFFloat(0)
+	_ fmt.Formatter = VaxDFloat(0)
+	_ fmt.Formatter = VaxGFloat(0)
+	_ fmt.Formatter = VaxHFloat{}
+)
+
+// Format implements fmt.Formatter for the F_Float.
+func (v VaxFFloat) Format(f fmt.State, verb rune) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(v))
+	fields := vaxFields{
+		raw:  buf[:],
+		bits: uint32(v),
+		sign: v.Sign(),
+		exp:  v.Exponent(),
+		frac: fmt.Sprintf("0x%06X", v.Fraction()),
+	}
+	if !v.IsReservedOperand() {
+		fields.value = v.bigFloat()
+	}
+	fields.format(f, verb)
+}
+
+// Format implements fmt.Formatter for the D_Float.
+func (v VaxDFloat) Format(f fmt.State, verb rune) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(v))
+	fields := vaxFields{
+		raw:  buf[:],
+		bits: uint64(v),
+		sign: v.Sign(),
+		exp:  v.Exponent(),
+		frac: fmt.Sprintf("0x%014X", v.Fraction()),
+	}
+	if !v.IsReservedOperand() {
+		fields.value = v.bigFloat()
+	}
+	fields.format(f, verb)
+}
+
+// Format implements fmt.Formatter for the G_Float.
+func (v VaxGFloat) Format(f fmt.State, verb rune) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(v))
+	fields := vaxFields{
+		raw:  buf[:],
+		bits: uint64(v),
+		sign: v.Sign(),
+		exp:  v.Exponent(),
+		frac: fmt.Sprintf("0x%013X", v.Fraction()),
+	}
+	if !v.IsReservedOperand() {
+		fields.value = v.bigFloat()
+	}
+	fields.format(f, verb)
+}
+
+// Format implements fmt.Formatter for the H_Float.
+func (v VaxHFloat) Format(f fmt.State, verb rune) {
+	part1 := uint32FromVaxbits(v[12:16])
+
+	fields := vaxFields{
+		raw:  v[:],
+		bits: [16]byte(v),
+		sign: uint(part1 >> 31),
+		exp:  int((part1 & VaxHExponentMask) >> VaxHMantissaSize),
+		frac: fmt.Sprintf("0x%04X%08X%08X%08X", part1&VaxHMantissaMask,
+			uint32FromVaxbits(v[8:12]), uint32FromVaxbits(v[4:8]), uint32FromVaxbits(v[0:4])),
+	}
+	if x, err := BigFloatfromVaxHFloat(v[:]); err == nil {
+		fields.value = x
+	}
+	fields.format(f, verb)
+}
+
+// vaxFields holds a VAX floating point value broken down for formatting.
+type vaxFields struct {
+	raw   []byte // bytes as stored on disk
+	bits  any    // integer underlying the type
+	sign  uint
+	exp   int
+	frac  string // fraction in hex, without the hidden bit
+	value any    // decimal value, or nil for a reserved operand
+}
+
+func (x vaxFields) format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('+'):
+		fmt.Fprintf(f, "s=%d e=%d f=%s ", x.sign, x.exp, x.frac)
+		switch {
+		case x.value == nil:
+			io.WriteString(f, "(reserved operand)")
+		case x.exp == 0 && !bytes.Equal(x.raw, make([]byte, len(x.raw))):
+			io.WriteString(f, "(dirty zero)")
+		default:
+			fmt.Fprintf(f, "(%v)", x.value)
+		}
+	case verb == 'x' || verb == 'X':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), x.raw)
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprintf(f, fmt.FormatString(f, verb), x.bits)
+	case verb == 'v' || verb == 's' || verb == 'e' || verb == 'E' || verb == 'f' ||
+		verb == 'F' || verb == 'g' || verb == 'G':
+		if x.value == nil {
+			fmt.Fprintf(f, "%s", appendHex(nil, x.raw))
+			return
+		}
+		if verb == 's' {
+			verb = 'v'
+		}
+		fmt.Fprintf(f, fmt.FormatString(f, verb), x.value)
+	default:
+		fmt.Fprintf(f, fmt.FormatString(f, verb), x.bits)
+	}
+}
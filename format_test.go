@@ -0,0 +1,46 @@
+package vaxdata
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestVaxFloatFormat(t *testing.T) {
+	var tests = []struct {
+		format string
+		v      any
+		want   string
+	}{
+		{"%v", VaxFFloat(0x0FD04149), "3.14159"},
+		{"%s", VaxFFloat(0x0FD04149), "3.14159"},
+		{"%.2f", VaxFFloat(0x0FD04149), "3.14"},
+		{"%e", VaxFFloat(0x0FD0C149), "-3.141590e+00"},
+		{"%x", VaxFFloat(0x0FD04149), "0fd04149"},
+		{"%#X", VaxFFloat(0x0FD04149), "0X0FD04149"},
+		{"%d", VaxFFloat(0x00000001), "1"},
+		{"%#v", VaxFFloat(0x0FD04149), "0xfd04149"},
+		{"%+v", VaxFFloat(0x0FD04149), "s=0 e=130 f=0x490FD0 (3.14159)"},
+		{"%+v", VaxFFloat(0x0FD0C149), "s=1 e=130 f=0x490FD0 (-3.14159)"},
+		{"%+v", VaxFFloat(0x00000000), "s=0 e=0 f=0x000000 (0)"},
+		{"%+v", VaxFFloat(0x00010000), "s=0 e=0 f=0x000001 (dirty zero)"},
+		{"%+v", VaxFFloat(0x00008000), "s=1 e=0 f=0x000000 (reserved operand)"},
+		{"%v", VaxFFloat(0x00008000), "0x00008000"},
+		{"%v", VaxFFloat(0xFFFF00FF), "5.8774714e-39"},
+		{"%g", VaxDFloat(0x68C0A2210FDA4149), "3.1415926535897931"},
+		{"%v", VaxDFloat(0x68C7A2210FDA4149), "3.1415926535897935"},
+		{"%+v", VaxDFloat(0x68C0A2210FDA4149), "s=0 e=130 f=0x490FDAA22168C0 (3.1415926535897931)"},
+		{"%v", VaxGFloat(0x2D18544421FB4029), "3.141592653589793"},
+		{"%x", VaxGFloat(0x2D18544421FB4029), "2d18544421fb4029"},
+		{"%+v", VaxGFloat(0x2D18544421FB4029), "s=0 e=1026 f=0x921FB54442D18 (3.141592653589793)"},
+		{"%+v", VaxGFloat(0x0000000000008000), "s=1 e=0 f=0x0000000000000 (reserved operand)"},
+		{"%+v", VaxGFloat(0x0001000000000000), "s=0 e=0 f=0x0000000000001 (dirty zero)"},
+		{"%v", VaxHFloat{14: 0x40}, "0.5"},
+		{"%+v", VaxHFloat{14: 0x40}, "s=0 e=16384 f=0x0000000000000000000000000000 (0.5)"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.v); got != tt.want {
+			t.Errorf("Sprintf(%q, %T) == %q, want %q", tt.format, tt.v, got, tt.want)
+		}
+	}
+}
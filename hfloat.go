@@ -0,0 +1,177 @@
+package vaxdata
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"math/big"
+)
+
+// VaxHFloatPrec is the precision, in bits, of a VAX H_Float mantissa
+// including the hidden bit.
+const VaxHFloatPrec = 113
+
+// VaxHFloatReader reads *big.Float values from H_Float's in the underlying
+// io.Reader.
+type VaxHFloatReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// NewVaxHFloatReader creates a new VaxHFloatReader. VaxHFloatReader.Read reads
+// a *big.Float from a H_Float in the underlying io.Reader.
+func NewVaxHFloatReader(r io.Reader) *VaxHFloatReader {
+	vaxin := new(VaxHFloatReader)
+	(*vaxin).r = r
+	(*vaxin).buf = make([]byte, 16)
+	return vaxin
+}
+
+// Read takes a H_Float from the underlying io.Reader and returns a *big.Float
+func (vaxin *VaxHFloatReader) Read() (*big.Float, error) {
+	if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+		return nil, err
+	}
+	return BigFloatfromVaxHFloat(vaxin.buf)
+}
+
+// WriteHFloat takes a *big.Float and writes an H_Float to the io.Writer.
+func WriteHFloat(w io.Writer, f *big.Float) error {
+	v, err := VaxHFloatfromBigFloat(f)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(v[:])
+	return err
+}
+
+// BigFloatfromVaxHFloat returns the exact *big.Float representation of a VAX
+// H_Float. The result has a precision of VaxHFloatPrec.
+func BigFloatfromVaxHFloat(buf []byte) (*big.Float, error) {
+	vaxpart1 := uint32FromVaxbits(buf[12:16])
+
+	if e := vaxpart1 & VaxHExponentMask; e == 0 {
+		// If the biased VAX exponent is zero [e=0]
+
+		if (vaxpart1 & SignBit) == SignBit {
+			// If negative [s=1]
+			// fixup to zero
+			fixup := new(big.Float).SetPrec(VaxHFloatPrec)
+			return fixup, &ConversionError{"H_Float", false, VaxHFloat(buf[:16]), fixup, ErrReservedOperand}
+		}
+
+		// Set VAX dirty [m<>0] or true [m=0] zero to +zero [s=e=m=0]
+		return new(big.Float).SetPrec(VaxHFloatPrec), nil
+	}
+
+	// Assemble the 0.1m mantissa as an integer, with the hidden bit restored
+	var bits [16]byte
+	binary.BigEndian.PutUint32(bits[0:4], (vaxpart1&VaxHMantissaMask)|VaxHHiddenBit)
+	binary.BigEndian.PutUint32(bits[4:8], uint32FromVaxbits(buf[8:12]))
+	binary.BigEndian.PutUint32(bits[8:12], uint32FromVaxbits(buf[4:8]))
+	binary.BigEndian.PutUint32(bits[12:16], uint32FromVaxbits(buf[0:4]))
+	m := new(big.Int).SetBytes(bits[:])
+
+	// (-1)^s * 2^(e-bias) * 0.1m, where 0.1m has VaxHFloatPrec bits
+	e := int((vaxpart1&VaxHExponentMask)>>VaxHMantissaSize) - int(VaxHExponentBias) - VaxHFloatPrec
+	result := new(big.Float).SetPrec(VaxHFloatPrec).SetInt(m)
+	result.SetMantExp(result, e)
+	if (vaxpart1 & SignBit) == SignBit {
+		result.Neg(result)
+	}
+
+	return result, nil
+}
+
+// VaxHFloatfromBigFloat returns the VAX H_Float representation of a
+// *big.Float. Values with more than VaxHFloatPrec bits of precision are
+// rounded to nearest, ties to even; see Rounding.
+func VaxHFloatfromBigFloat(f *big.Float) (VaxHFloat, error) {
+	return vaxHFloatfromBigFloat(f, big.ToNearestEven)
+}
+
+// vaxHFloatfromBigFloat is VaxHFloatfromBigFloat, rounding by mode.
+func vaxHFloatfromBigFloat(f *big.Float, mode big.RoundingMode) (VaxHFloat, error) {
+	var (
+		result                                 VaxHFloat
+		vaxpart1, vaxpart2, vaxpart3, vaxpart4 uint32
+		err                                    error
+	)
+
+	var sign uint32
+	if f.Signbit() {
+		sign = SignBit
+	}
+
+	if f.IsInf() {
+		// VAX's have no equivalents for +-Infinity [e=all-1's]
+		err = ErrNotFinite
+
+		// Fixup to VAX +-extrema [e=all-1's] with zero mantissa [m=0]
+		vaxpart1 = sign | VaxHExponentMask
+	} else if f.Sign() != 0 {
+		// Round to an H_Float mantissa and split off the 0.1m form
+		mant := new(big.Float).SetMode(mode).SetPrec(VaxHFloatPrec).Set(f)
+		e := mant.MantExp(mant) + int(VaxHExponentBias)
+
+		if e <= 0 {
+			// Silent underflow
+		} else if e > int(2*VaxHExponentBias-1) {
+			err = ErrOverflow
+
+			// Overflow; fixup to VAX +-extrema [e=m=all-1's]
+			vaxpart1 = sign | ^SignBit
+			vaxpart2, vaxpart3, vaxpart4 = math.MaxUint32, math.MaxUint32, math.MaxUint32
+		} else {
+			// VAX normalized form [e>0], dropping the hidden bit
+			m, _ := mant.Abs(mant).SetMantExp(mant, VaxHFloatPrec).Int(nil)
+			var bits [16]byte
+			m.FillBytes(bits[:])
+
+			vaxpart1 = sign | (uint32(e) << VaxHMantissaSize) | uint32(binary.BigEndian.Uint16(bits[2:4]))
+			vaxpart2 = binary.BigEndian.Uint32(bits[4:8])
+			vaxpart3 = binary.BigEndian.Uint32(bits[8:12])
+			vaxpart4 = binary.BigEndian.Uint32(bits[12:16])
+		}
+	}
+
+	binary.BigEndian.PutUint32(result[0:4], uint32FromVax(vaxpart4))
+	binary.BigEndian.PutUint32(result[4:8], uint32FromVax(vaxpart3))
+	binary.BigEndian.PutUint32(result[8:12], uint32FromVax(vaxpart2))
+	binary.BigEndian.PutUint32(result[12:16], uint32FromVax(vaxpart1))
+	if err != nil {
+		return result, &ConversionError{"H_Float", true, f, result, err}
+	}
+	return result, nil
+}
+
+// Float64fromVaxHFloat returns the float64 nearest to a VAX H_Float. The
+// big.Accuracy reports whether precision was lost, and an error is returned
+// when the value lies outside the range of a float64, in which case the
+// result is +-Inf or +-0. See Rounding for other rounding modes.
+func Float64fromVaxHFloat(buf []byte) (float64, big.Accuracy, error) {
+	return float64fromVaxHFloat(buf, big.ToNearestEven)
+}
+
+// float64fromVaxHFloat is Float64fromVaxHFloat, rounding by mode.
+func float64fromVaxHFloat(buf []byte, mode big.RoundingMode) (float64, big.Accuracy, error) {
+	x, err := BigFloatfromVaxHFloat(buf)
+	if err != nil {
+		return 0, big.Exact, &ConversionError{"H_Float", false, VaxHFloat(buf[:16]), float64(0), ErrReservedOperand}
+	}
+
+	f, acc := roundFloat64(x, mode)
+	if math.IsInf(f, 0) || x.MantExp(nil) > 1024 {
+		// Modes rounding toward zero hold values of 2**1024 and above
+		// to +-math.MaxFloat64
+		err = ErrOverflow
+	} else if f == 0 && x.Sign() != 0 {
+		err = ErrUnderflow
+	}
+
+	if err != nil {
+		return f, acc, &ConversionError{"H_Float", false, VaxHFloat(buf[:16]), f, err}
+	}
+	return f, acc, nil
+}
@@ -0,0 +1,270 @@
+package vaxdata
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// VAX integers are two's complement and stored LittleEndian, so unlike the
+// floating point formats they need no word swapping.
+
+// VaxOctaword represents a VAX OCTAWORD 128-bit value
+type VaxOctaword struct {
+	Lo, Hi uint64
+}
+
+// VaxOctawordfromBigInt returns the VAX OCTAWORD representation of a
+// *big.Int. Both the signed and unsigned 128-bit ranges are accepted.
+func VaxOctawordfromBigInt(x *big.Int) (VaxOctaword, error) {
+	if x.BitLen() > 128 || (x.Sign() < 0 && new(big.Int).Not(x).BitLen() > 127) {
+		return VaxOctaword{}, errors.New("big.Int too large for VAX OCTAWORD")
+	}
+
+	var buf [16]byte
+	if x.Sign() < 0 {
+		// Two's complement of -x is the complement of (-x - 1)
+		new(big.Int).Not(x).FillBytes(buf[:])
+		for i := range buf {
+			buf[i] = ^buf[i]
+		}
+	} else {
+		x.FillBytes(buf[:])
+	}
+
+	return VaxOctaword{
+		Lo: binary.BigEndian.Uint64(buf[8:16]),
+		Hi: binary.BigEndian.Uint64(buf[0:8]),
+	}, nil
+}
+
+// Int returns the signed value of the OCTAWORD.
+func (o VaxOctaword) Int() *big.Int {
+	x := o.Uint()
+	if int64(o.Hi) < 0 {
+		x.Sub(x, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+	return x
+}
+
+// Uint returns the unsigned value of the OCTAWORD.
+func (o VaxOctaword) Uint() *big.Int {
+	x := new(big.Int).SetUint64(o.Hi)
+	x.Lsh(x, 64)
+	return x.Or(x, new(big.Int).SetUint64(o.Lo))
+}
+
+// VaxByteReader reads 8-bit values from BYTE's in the underlying io.Reader.
+type VaxByteReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// NewVaxByteReader creates a new VaxByteReader. VaxByteReader.Read reads an
+// int8 from a BYTE in the underlying io.Reader.
+func NewVaxByteReader(r io.Reader) *VaxByteReader {
+	vaxin := new(VaxByteReader)
+	(*vaxin).r = r
+	(*vaxin).buf = make([]byte, 1)
+	return vaxin
+}
+
+// Read takes a BYTE from the underlying io.Reader and returns an int8.
+func (vaxin *VaxByteReader) Read() (int8, error) {
+	v, err := vaxin.ReadUnsigned()
+	return int8(v), err
+}
+
+// ReadUnsigned takes a BYTE from the underlying io.Reader and returns a uint8.
+func (vaxin *VaxByteReader) ReadUnsigned() (uint8, error) {
+	if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+		return 0, err
+	}
+	return vaxin.buf[0], nil
+}
+
+// VaxWordReader reads 16-bit values from WORD's in the underlying io.Reader.
+type VaxWordReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// NewVaxWordReader creates a new VaxWordReader. VaxWordReader.Read reads an
+// int16 from a WORD in the underlying io.Reader.
+func NewVaxWordReader(r io.Reader) *VaxWordReader {
+	vaxin := new(VaxWordReader)
+	(*vaxin).r = r
+	(*vaxin).buf = make([]byte, 2)
+	return vaxin
+}
+
+// Read takes a WORD from the underlying io.Reader and returns an int16.
+func (vaxin *VaxWordReader) Read() (int16, error) {
+	v, err := vaxin.ReadUnsigned()
+	return int16(v), err
+}
+
+// ReadUnsigned takes a WORD from the underlying io.Reader and returns a uint16.
+func (vaxin *VaxWordReader) ReadUnsigned() (uint16, error) {
+	if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(vaxin.buf), nil
+}
+
+// VaxLongwordReader reads 32-bit values from LONGWORD's in the underlying
+// io.Reader.
+type VaxLongwordReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// NewVaxLongwordReader creates a new VaxLongwordReader. VaxLongwordReader.Read
+// reads an int32 from a LONGWORD in the underlying io.Reader.
+func NewVaxLongwordReader(r io.Reader) *VaxLongwordReader {
+	vaxin := new(VaxLongwordReader)
+	(*vaxin).r = r
+	(*vaxin).buf = make([]byte, 4)
+	return vaxin
+}
+
+// Read takes a LONGWORD from the underlying io.Reader and returns an int32.
+func (vaxin *VaxLongwordReader) Read() (int32, error) {
+	v, err := vaxin.ReadUnsigned()
+	return int32(v), err
+}
+
+// ReadUnsigned takes a LONGWORD from the underlying io.Reader and returns a
+// uint32.
+func (vaxin *VaxLongwordReader) ReadUnsigned() (uint32, error) {
+	if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(vaxin.buf), nil
+}
+
+// VaxQuadwordReader reads 64-bit values from QUADWORD's in the underlying
+// io.Reader.
+type VaxQuadwordReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// NewVaxQuadwordReader creates a new VaxQuadwordReader. VaxQuadwordReader.Read
+// reads an int64 from a QUADWORD in the underlying io.Reader.
+func NewVaxQuadwordReader(r io.Reader) *VaxQuadwordReader {
+	vaxin := new(VaxQuadwordReader)
+	(*vaxin).r = r
+	(*vaxin).buf = make([]byte, 8)
+	return vaxin
+}
+
+// Read takes a QUADWORD from the underlying io.Reader and returns an int64.
+func (vaxin *VaxQuadwordReader) Read() (int64, error) {
+	v, err := vaxin.ReadUnsigned()
+	return int64(v), err
+}
+
+// ReadUnsigned takes a QUADWORD from the underlying io.Reader and returns a
+// uint64.
+func (vaxin *VaxQuadwordReader) ReadUnsigned() (uint64, error) {
+	if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(vaxin.buf), nil
+}
+
+// VaxOctawordReader reads 128-bit values from OCTAWORD's in the underlying
+// io.Reader.
+type VaxOctawordReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// NewVaxOctawordReader creates a new VaxOctawordReader. VaxOctawordReader.Read
+// reads a VaxOctaword from an OCTAWORD in the underlying io.Reader.
+func NewVaxOctawordReader(r io.Reader) *VaxOctawordReader {
+	vaxin := new(VaxOctawordReader)
+	(*vaxin).r = r
+	(*vaxin).buf = make([]byte, 16)
+	return vaxin
+}
+
+// Read takes an OCTAWORD from the underlying io.Reader and returns a
+// VaxOctaword.
+func (vaxin *VaxOctawordReader) Read() (VaxOctaword, error) {
+	if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+		return VaxOctaword{}, err
+	}
+	return VaxOctaword{
+		Lo: binary.LittleEndian.Uint64(vaxin.buf[0:8]),
+		Hi: binary.LittleEndian.Uint64(vaxin.buf[8:16]),
+	}, nil
+}
+
+// ReadInt takes an OCTAWORD from the underlying io.Reader and returns its
+// signed value.
+func (vaxin *VaxOctawordReader) ReadInt() (*big.Int, error) {
+	v, err := vaxin.Read()
+	if err != nil {
+		return nil, err
+	}
+	return v.Int(), nil
+}
+
+// ReadUnsigned takes an OCTAWORD from the underlying io.Reader and returns
+// its unsigned value.
+func (vaxin *VaxOctawordReader) ReadUnsigned() (*big.Int, error) {
+	v, err := vaxin.Read()
+	if err != nil {
+		return nil, err
+	}
+	return v.Uint(), nil
+}
+
+// WriteByte takes an int8 and writes a BYTE to the io.Writer.
+func WriteByte(w io.Writer, v int8) error {
+	return WriteUnsignedByte(w, uint8(v))
+}
+
+// WriteUnsignedByte takes a uint8 and writes a BYTE to the io.Writer.
+func WriteUnsignedByte(w io.Writer, v uint8) error {
+	_, err := w.Write([]byte{v})
+	return err
+}
+
+// WriteWord takes an int16 and writes a WORD to the io.Writer.
+func WriteWord(w io.Writer, v int16) error {
+	return WriteUnsignedWord(w, uint16(v))
+}
+
+// WriteUnsignedWord takes a uint16 and writes a WORD to the io.Writer.
+func WriteUnsignedWord(w io.Writer, v uint16) error {
+	return binary.Write(w, binary.LittleEndian, v)
+}
+
+// WriteLongword takes an int32 and writes a LONGWORD to the io.Writer.
+func WriteLongword(w io.Writer, v int32) error {
+	return WriteUnsignedLongword(w, uint32(v))
+}
+
+// WriteUnsignedLongword takes a uint32 and writes a LONGWORD to the io.Writer.
+func WriteUnsignedLongword(w io.Writer, v uint32) error {
+	return binary.Write(w, binary.LittleEndian, v)
+}
+
+// WriteQuadword takes an int64 and writes a QUADWORD to the io.Writer.
+func WriteQuadword(w io.Writer, v int64) error {
+	return WriteUnsignedQuadword(w, uint64(v))
+}
+
+// WriteUnsignedQuadword takes a uint64 and writes a QUADWORD to the io.Writer.
+func WriteUnsignedQuadword(w io.Writer, v uint64) error {
+	return binary.Write(w, binary.LittleEndian, v)
+}
+
+// WriteOctaword takes a VaxOctaword and writes an OCTAWORD to the io.Writer.
+func WriteOctaword(w io.Writer, v VaxOctaword) error {
+	return binary.Write(w, binary.LittleEndian, [2]uint64{v.Lo, v.Hi})
+}
@@ -0,0 +1,117 @@
+package vaxdata
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"testing"
+)
+
+func TestVaxIntegers(t *testing.T) {
+	var buf bytes.Buffer
+	writes := []struct {
+		name  string
+		write func() error
+	}{
+		{"WriteByte", func() error { return WriteByte(&buf, -2) }},
+		{"WriteUnsignedByte", func() error { return WriteUnsignedByte(&buf, 0xFE) }},
+		{"WriteWord", func() error { return WriteWord(&buf, -2) }},
+		{"WriteUnsignedWord", func() error { return WriteUnsignedWord(&buf, 0x1234) }},
+		{"WriteLongword", func() error { return WriteLongword(&buf, -2) }},
+		{"WriteUnsignedLongword", func() error { return WriteUnsignedLongword(&buf, 0x12345678) }},
+		{"WriteQuadword", func() error { return WriteQuadword(&buf, -2) }},
+		{"WriteUnsignedQuadword", func() error { return WriteUnsignedQuadword(&buf, 0x0123456789ABCDEF) }},
+	}
+	for _, w := range writes {
+		if err := w.write(); err != nil {
+			t.Fatalf("%s raised unexpected error: %q", w.name, err)
+		}
+	}
+
+	want := []byte{
+		0xFE,
+		0xFE,
+		0xFE, 0xFF,
+		0x34, 0x12,
+		0xFE, 0xFF, 0xFF, 0xFF,
+		0x78, 0x56, 0x34, 0x12,
+		0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+		0xEF, 0xCD, 0xAB, 0x89, 0x67, 0x45, 0x23, 0x01,
+	}
+	if !sliceByteEquals(buf.Bytes(), want) {
+		t.Fatalf("Write == %X, want %X", buf.Bytes(), want)
+	}
+
+	r := bytes.NewReader(want)
+	if v, err := NewVaxByteReader(r).Read(); v != -2 || err != nil {
+		t.Errorf("VaxByteReader.Read() == %v, %v, want -2", v, err)
+	}
+	if v, err := NewVaxByteReader(r).ReadUnsigned(); v != 0xFE || err != nil {
+		t.Errorf("VaxByteReader.ReadUnsigned() == %v, %v, want 0xFE", v, err)
+	}
+	if v, err := NewVaxWordReader(r).Read(); v != -2 || err != nil {
+		t.Errorf("VaxWordReader.Read() == %v, %v, want -2", v, err)
+	}
+	if v, err := NewVaxWordReader(r).ReadUnsigned(); v != 0x1234 || err != nil {
+		t.Errorf("VaxWordReader.ReadUnsigned() == %v, %v, want 0x1234", v, err)
+	}
+	if v, err := NewVaxLongwordReader(r).Read(); v != -2 || err != nil {
+		t.Errorf("VaxLongwordReader.Read() == %v, %v, want -2", v, err)
+	}
+	if v, err := NewVaxLongwordReader(r).ReadUnsigned(); v != 0x12345678 || err != nil {
+		t.Errorf("VaxLongwordReader.ReadUnsigned() == %v, %v, want 0x12345678", v, err)
+	}
+	if v, err := NewVaxQuadwordReader(r).Read(); v != -2 || err != nil {
+		t.Errorf("VaxQuadwordReader.Read() == %v, %v, want -2", v, err)
+	}
+	if v, err := NewVaxQuadwordReader(r).ReadUnsigned(); v != 0x0123456789ABCDEF || err != nil {
+		t.Errorf("VaxQuadwordReader.ReadUnsigned() == %v, %v, want 0x0123456789ABCDEF", v, err)
+	}
+	if _, err := NewVaxLongwordReader(r).Read(); err != io.EOF {
+		t.Errorf("Unexpected error %q", err)
+	}
+}
+
+func TestVaxOctaword(t *testing.T) {
+	cases := []struct {
+		in   string
+		want VaxOctaword
+		err  bool
+	}{
+		{"0", VaxOctaword{}, false},
+		{"-1", VaxOctaword{0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF}, false},
+		{"18446744073709551616", VaxOctaword{0, 1}, false},
+		{"-170141183460469231731687303715884105728", VaxOctaword{0, 0x8000000000000000}, false},
+		{"340282366920938463463374607431768211455", VaxOctaword{0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF}, false},
+		{"340282366920938463463374607431768211456", VaxOctaword{}, true},
+		{"-170141183460469231731687303715884105729", VaxOctaword{}, true},
+	}
+	for _, c := range cases {
+		in, _ := new(big.Int).SetString(c.in, 10)
+		got, err := VaxOctawordfromBigInt(in)
+		if (err != nil) != c.err {
+			t.Errorf("VaxOctawordfromBigInt(%s) error == %v, want error %v", c.in, err, c.err)
+			continue
+		} else if err != nil {
+			continue
+		}
+		if got != c.want {
+			t.Errorf("VaxOctawordfromBigInt(%s) == %+v, want %+v", c.in, got, c.want)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteOctaword(&buf, got); err != nil {
+			t.Fatalf("WriteOctaword(%+v) raised unexpected error: %q", got, err)
+		}
+		r := NewVaxOctawordReader(&buf)
+		var reverse *big.Int
+		if in.Sign() < 0 {
+			reverse, err = r.ReadInt()
+		} else {
+			reverse, err = r.ReadUnsigned()
+		}
+		if err != nil || reverse.Cmp(in) != 0 {
+			t.Errorf("VaxOctawordReader(%+v) == %v, %v, want %s", got, reverse, err, c.in)
+		}
+	}
+}
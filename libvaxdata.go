@@ -0,0 +1,167 @@
+package vaxdata
+
+import (
+	"encoding/binary"
+	"io"
+	"math/big"
+)
+
+// The functions below mirror the libvaxdata array API, taking slices in
+// place of the input buffer, output buffer and count pointer:
+//
+// 	from_vax_i2( inbuf, outbuf, count )  ->  FromVaxI2( in, out )
+// 	to_vax_r4( inbuf, outbuf, count )    ->  ToVaxR4( in, out )
+//
+// The count is the length of the native slice. As with libvaxdata every
+// element is converted, with faults fixed up as by the single value
+// functions; the first fault is returned as a *ValueError once the array is
+// complete.
+
+// FromVaxI2 converts VAX WORD's in to int16's in out.
+func FromVaxI2(in []byte, out []int16) error {
+	if len(in) < 2*len(out) {
+		return io.ErrShortBuffer
+	}
+
+	for i := range out {
+		out[i] = int16(binary.LittleEndian.Uint16(in[2*i:]))
+	}
+	return nil
+}
+
+// FromVaxI4 converts VAX LONGWORD's in to int32's in out.
+func FromVaxI4(in []byte, out []int32) error {
+	if len(in) < 4*len(out) {
+		return io.ErrShortBuffer
+	}
+
+	for i := range out {
+		out[i] = int32(binary.LittleEndian.Uint32(in[4*i:]))
+	}
+	return nil
+}
+
+// FromVaxR4 converts VAX F_Float's in to float32's in out.
+func FromVaxR4(in []byte, out []float32) error {
+	if len(in) < 4*len(out) {
+		return io.ErrShortBuffer
+	}
+
+	_, err := DecodeFFloats(out, in)
+	return err
+}
+
+// FromVaxD8 converts VAX D_Float's in to float64's in out.
+func FromVaxD8(in []byte, out []float64) error {
+	if len(in) < 8*len(out) {
+		return io.ErrShortBuffer
+	}
+
+	_, err := DecodeDFloats(out, in)
+	return err
+}
+
+// FromVaxG8 converts VAX G_Float's in to float64's in out.
+func FromVaxG8(in []byte, out []float64) error {
+	if len(in) < 8*len(out) {
+		return io.ErrShortBuffer
+	}
+
+	_, err := DecodeGFloats(out, in)
+	return err
+}
+
+// FromVaxH16 converts VAX H_Float's in to *big.Float's in out. Unlike
+// libvaxdata, which produces IEEE X_Float's, the results are exact.
+func FromVaxH16(in []byte, out []*big.Float) error {
+	if len(in) < 16*len(out) {
+		return io.ErrShortBuffer
+	}
+
+	var first error
+	for i := range out {
+		var err error
+		if out[i], err = BigFloatfromVaxHFloat(in[16*i : 16*i+16]); err != nil && first == nil {
+			first = &ValueError{i, err}
+		}
+	}
+	return first
+}
+
+// ToVaxI2 converts int16's in to VAX WORD's in out.
+func ToVaxI2(in []int16, out []byte) error {
+	if len(out) < 2*len(in) {
+		return io.ErrShortBuffer
+	}
+
+	for i, v := range in {
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(v))
+	}
+	return nil
+}
+
+// ToVaxI4 converts int32's in to VAX LONGWORD's in out.
+func ToVaxI4(in []int32, out []byte) error {
+	if len(out) < 4*len(in) {
+		return io.ErrShortBuffer
+	}
+
+	for i, v := range in {
+		binary.LittleEndian.PutUint32(out[4*i:], uint32(v))
+	}
+	return nil
+}
+
+// ToVaxR4 converts float32's in to VAX F_Float's in out.
+func ToVaxR4(in []float32, out []byte) error {
+	if len(out) < 4*len(in) {
+		return io.ErrShortBuffer
+	}
+
+	_, err := EncodeFFloats(out, in)
+	return err
+}
+
+// ToVaxD8 converts float64's in to VAX D_Float's in out.
+func ToVaxD8(in []float64, out []byte) error {
+	if len(out) < 8*len(in) {
+		return io.ErrShortBuffer
+	}
+
+	_, err := EncodeDFloats(out, in)
+	return err
+}
+
+// ToVaxG8 converts float64's in to VAX G_Float's in out.
+func ToVaxG8(in []float64, out []byte) error {
+	if len(out) < 8*len(in) {
+		return io.ErrShortBuffer
+	}
+
+	_, err := EncodeGFloats(out, in)
+	return err
+}
+
+// ToVaxH16 converts *big.Float's in to VAX H_Float's in out.
+func ToVaxH16(in []*big.Float, out []byte) error {
+	if len(out) < 16*len(in) {
+		return io.ErrShortBuffer
+	}
+
+	var first error
+	for i, f := range in {
+		v, err := VaxHFloatfromBigFloat(f)
+		if err != nil && first == nil {
+			first = &ValueError{i, err}
+		}
+		copy(out[16*i:], v[:])
+	}
+	return first
+}
+
+// IsLittleEndian reports whether the host is little-endian, as does
+// libvaxdata's is_little_endian. The conversions in this package do not
+// depend on it.
+func IsLittleEndian() bool {
+	return binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+}
@@ -0,0 +1,96 @@
+package vaxdata
+
+import (
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestLibvaxdataArrays(t *testing.T) {
+	r4 := []float32{1, -3.5, 3.14159}
+	vaxr4 := make([]byte, 4*len(r4))
+	if err := ToVaxR4(r4, vaxr4); err != nil {
+		t.Errorf("ToVaxR4(%v) raised unexpected error: %q", r4, err)
+	}
+	for i, f := range r4 {
+		v, _ := VaxFFloatfromFloat32(f)
+		if got := uint32FromVax(uint32FromVaxbits(vaxr4[4*i:])); got != uint32(v) {
+			t.Errorf("ToVaxR4(%v)[%d] == %08X, want %08X", r4, i, got, v)
+		}
+	}
+	outr4 := make([]float32, len(r4))
+	if err := FromVaxR4(vaxr4, outr4); err != nil || !sliceEquals(outr4, r4) {
+		t.Errorf("FromVaxR4(%X) == %v, %v, want %v", vaxr4, outr4, err, r4)
+	}
+
+	r8 := []float64{1, -3.5, math.Pi, 1e-37}
+	vax8 := make([]byte, 8*len(r8))
+	out8 := make([]float64, len(r8))
+	for _, c := range []struct {
+		name string
+		to   func([]float64, []byte) error
+		from func([]byte, []float64) error
+	}{
+		{"D8", ToVaxD8, FromVaxD8},
+		{"G8", ToVaxG8, FromVaxG8},
+	} {
+		if err := c.to(r8, vax8); err != nil {
+			t.Errorf("ToVax%s(%v) raised unexpected error: %q", c.name, r8, err)
+		}
+		if err := c.from(vax8, out8); err != nil || !slice64Equals(out8, r8) {
+			t.Errorf("FromVax%s(%X) == %v, %v, want %v", c.name, vax8, out8, err, r8)
+		}
+	}
+
+	i2 := []int16{1, -2, 0x1234}
+	vaxi2 := make([]byte, 2*len(i2))
+	outi2 := make([]int16, len(i2))
+	if err := ToVaxI2(i2, vaxi2); err != nil || string(vaxi2) != "\x01\x00\xFE\xFF\x34\x12" {
+		t.Errorf("ToVaxI2(%v) == %X, %v", i2, vaxi2, err)
+	}
+	if err := FromVaxI2(vaxi2, outi2); err != nil || outi2[0] != 1 || outi2[1] != -2 || outi2[2] != 0x1234 {
+		t.Errorf("FromVaxI2(%X) == %v, %v, want %v", vaxi2, outi2, err, i2)
+	}
+
+	i4 := []int32{1, -2, 0x12345678}
+	vaxi4 := make([]byte, 4*len(i4))
+	outi4 := make([]int32, len(i4))
+	if err := ToVaxI4(i4, vaxi4); err != nil || string(vaxi4) != "\x01\x00\x00\x00\xFE\xFF\xFF\xFF\x78\x56\x34\x12" {
+		t.Errorf("ToVaxI4(%v) == %X, %v", i4, vaxi4, err)
+	}
+	if err := FromVaxI4(vaxi4, outi4); err != nil || outi4[0] != 1 || outi4[1] != -2 || outi4[2] != 0x12345678 {
+		t.Errorf("FromVaxI4(%X) == %v, %v, want %v", vaxi4, outi4, err, i4)
+	}
+
+	h16 := []*big.Float{big.NewFloat(1), big.NewFloat(-math.Pi)}
+	vaxh16 := make([]byte, 16*len(h16))
+	outh16 := make([]*big.Float, len(h16))
+	if err := ToVaxH16(h16, vaxh16); err != nil {
+		t.Errorf("ToVaxH16(%v) raised unexpected error: %q", h16, err)
+	}
+	if err := FromVaxH16(vaxh16, outh16); err != nil || outh16[0].Cmp(h16[0]) != 0 || outh16[1].Cmp(h16[1]) != 0 {
+		t.Errorf("FromVaxH16(%X) == %v, %v, want %v", vaxh16, outh16, err, h16)
+	}
+}
+
+func TestLibvaxdataArrayFaults(t *testing.T) {
+	// libvaxdata converts every element, fixing up the faults
+	in := []float32{1, float32(math.Inf(1)), 2, float32(math.NaN())}
+	out := make([]byte, 4*len(in))
+	err := ToVaxR4(in, out)
+	if err == nil || !strings.HasPrefix(err.Error(), "element 1: ") {
+		t.Errorf("ToVaxR4(%v) == %v, want an element 1 error", in, err)
+	}
+	reverse := make([]float32, len(in))
+	FromVaxR4(out, reverse)
+	if reverse[0] != 1 || reverse[2] != 2 {
+		t.Errorf("ToVaxR4(%v) did not convert every element: %v", in, reverse)
+	}
+
+	if err := FromVaxR4(out[:4], reverse); !errors.Is(err, io.ErrShortBuffer) {
+		t.Errorf("FromVaxR4 with a short buffer == %v, want io.ErrShortBuffer", err)
+	}
+}
@@ -0,0 +1,188 @@
+package vaxdata
+
+import (
+	"errors"
+	"math/big"
+)
+
+// VAX numeric strings hold one ASCII digit per byte. Leading separate
+// numeric strings carry the sign in an extra byte before the digits, while
+// trailing numeric strings fold it into the last digit, either overpunched
+// (as on a punched card) or zoned (in the high-order nibble).
+
+// overpunchPlus and overpunchMinus are the preferred trailing numeric
+// overpunch characters for the digits 0 through 9.
+const (
+	overpunchPlus  = "{ABCDEFGHI"
+	overpunchMinus = "}JKLMNOPQR"
+)
+
+// overpunch returns the digit and sign of a trailing numeric overpunch
+// character, including the alternate forms accepted by the VAX CVTTP
+// instruction.
+func overpunch(c byte) (digit byte, negative, ok bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', false, true
+	case c >= 'A' && c <= 'I':
+		return c - 'A' + 1, false, true
+	case c >= 'J' && c <= 'R':
+		return c - 'J' + 1, true, true
+	case c == '{' || c == '[' || c == '?':
+		return 0, false, true
+	case c == '}' || c == ']' || c == ':':
+		return 0, true, true
+	}
+	return 0, false, false
+}
+
+// decodeDigits accumulates the ASCII digits of buf onto v. The offset of buf
+// within its field is used to report errors.
+func decodeDigits(v *big.Int, buf []byte, offset int) error {
+	ten := big.NewInt(10)
+	digit := new(big.Int)
+	for i, c := range buf {
+		if c < '0' || c > '9' {
+			return &DecimalDigitError{Offset: offset + i, Digit: c}
+		}
+		v.Mul(v, ten).Add(v, digit.SetInt64(int64(c-'0')))
+	}
+	return nil
+}
+
+// DecodeLeadingSeparateNumeric returns the Decimal held in a VAX leading
+// separate numeric string with the given scale. The sign byte is one of '+',
+// '-' or ' ' (positive).
+func DecodeLeadingSeparateNumeric(buf []byte, scale int) (Decimal, error) {
+	if len(buf) == 0 {
+		return Decimal{}, errors.New("empty VAX leading separate numeric string")
+	}
+
+	v := new(big.Int)
+	if err := decodeDigits(v, buf[1:], 1); err != nil {
+		return Decimal{}, err
+	}
+
+	switch buf[0] {
+	case '+', ' ':
+	case '-':
+		v.Neg(v)
+	default:
+		return Decimal{}, &DecimalSignError{Offset: 0, Sign: buf[0]}
+	}
+
+	return Decimal{Value: v, Scale: scale}, nil
+}
+
+// EncodeLeadingSeparateNumeric returns the VAX leading separate numeric
+// string representation of d with the given number of digits, preceded by a
+// '+' or '-' sign byte. The digits of d.Value are stored as is; d.Scale is
+// implied by the field.
+func EncodeLeadingSeparateNumeric(d Decimal, digits int) ([]byte, error) {
+	ascii, err := decimalDigits(d.Value, digits, "leading separate numeric string")
+	if err != nil {
+		return nil, err
+	}
+
+	sign := byte('+')
+	if d.negative() {
+		sign = '-'
+	}
+	return append([]byte{sign}, ascii...), nil
+}
+
+// DecodeTrailingNumeric returns the Decimal held in a VAX trailing numeric
+// string, with the sign overpunched on the last digit, with the given scale.
+func DecodeTrailingNumeric(buf []byte, scale int) (Decimal, error) {
+	if len(buf) == 0 {
+		return Decimal{}, errors.New("empty VAX trailing numeric string")
+	}
+
+	last := len(buf) - 1
+	digit, negative, ok := overpunch(buf[last])
+	if !ok {
+		return Decimal{}, &DecimalSignError{Offset: last, Sign: buf[last]}
+	}
+
+	v := new(big.Int)
+	if err := decodeDigits(v, buf[:last], 0); err != nil {
+		return Decimal{}, err
+	}
+	v.Mul(v, big.NewInt(10)).Add(v, big.NewInt(int64(digit)))
+	if negative {
+		v.Neg(v)
+	}
+
+	return Decimal{Value: v, Scale: scale}, nil
+}
+
+// EncodeTrailingNumeric returns the VAX trailing numeric string
+// representation of d with the given number of digits, using the preferred
+// overpunch characters for the last digit. The digits of d.Value are stored
+// as is; d.Scale is implied by the field.
+func EncodeTrailingNumeric(d Decimal, digits int) ([]byte, error) {
+	if digits < 1 {
+		return nil, errors.New("VAX trailing numeric string must have at least one digit")
+	}
+
+	ascii, err := decimalDigits(d.Value, digits, "trailing numeric string")
+	if err != nil {
+		return nil, err
+	}
+
+	last := ascii[digits-1] - '0'
+	if d.negative() {
+		ascii[digits-1] = overpunchMinus[last]
+	} else {
+		ascii[digits-1] = overpunchPlus[last]
+	}
+	return ascii, nil
+}
+
+// DecodeZonedNumeric returns the Decimal held in a VAX zoned numeric string,
+// with the sign in the high-order nibble of the last digit, with the given
+// scale. A zone of 0x3 is positive and 0x7 is negative.
+func DecodeZonedNumeric(buf []byte, scale int) (Decimal, error) {
+	if len(buf) == 0 {
+		return Decimal{}, errors.New("empty VAX zoned numeric string")
+	}
+
+	last := len(buf) - 1
+	digit, zone := buf[last]&0x0F, buf[last]>>4
+	if digit > 9 {
+		return Decimal{}, &DecimalDigitError{Offset: last, Digit: buf[last]}
+	}
+	if zone != 0x3 && zone != 0x7 {
+		return Decimal{}, &DecimalSignError{Offset: last, Sign: buf[last]}
+	}
+
+	v := new(big.Int)
+	if err := decodeDigits(v, buf[:last], 0); err != nil {
+		return Decimal{}, err
+	}
+	v.Mul(v, big.NewInt(10)).Add(v, big.NewInt(int64(digit)))
+	if zone == 0x7 {
+		v.Neg(v)
+	}
+
+	return Decimal{Value: v, Scale: scale}, nil
+}
+
+// EncodeZonedNumeric returns the VAX zoned numeric string representation of
+// d with the given number of digits. The digits of d.Value are stored as is;
+// d.Scale is implied by the field.
+func EncodeZonedNumeric(d Decimal, digits int) ([]byte, error) {
+	if digits < 1 {
+		return nil, errors.New("VAX zoned numeric string must have at least one digit")
+	}
+
+	ascii, err := decimalDigits(d.Value, digits, "zoned numeric string")
+	if err != nil {
+		return nil, err
+	}
+
+	if d.negative() {
+		ascii[digits-1] = 0x70 | (ascii[digits-1] & 0x0F)
+	}
+	return ascii, nil
+}
@@ -0,0 +1,97 @@
+package vaxdata
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestNumericStrings(t *testing.T) {
+	cases := []struct {
+		value    int64
+		scale    int
+		leading  string
+		trailing string
+		zoned    string
+	}{
+		{12345, 2, "+12345", "1234E", "12345"},
+		{-12345, 2, "-12345", "1234N", "1234u"},
+		{-10, 0, "-00010", "0001}", "0001p"},
+		{0, 0, "+00000", "0000{", "00000"},
+	}
+	for _, c := range cases {
+		d := Decimal{Value: big.NewInt(c.value), Scale: c.scale}
+		codecs := []struct {
+			name   string
+			want   string
+			encode func(Decimal, int) ([]byte, error)
+			decode func([]byte, int) (Decimal, error)
+		}{
+			{"LeadingSeparateNumeric", c.leading, EncodeLeadingSeparateNumeric, DecodeLeadingSeparateNumeric},
+			{"TrailingNumeric", c.trailing, EncodeTrailingNumeric, DecodeTrailingNumeric},
+			{"ZonedNumeric", c.zoned, EncodeZonedNumeric, DecodeZonedNumeric},
+		}
+		for _, codec := range codecs {
+			got, err := codec.encode(d, 5)
+			if err != nil {
+				t.Errorf("Encode%s(%v, 5) raised unexpected error: %q", codec.name, d, err)
+			} else if string(got) != codec.want {
+				t.Errorf("Encode%s(%v, 5) == %q, want %q", codec.name, d, got, codec.want)
+			}
+
+			reverse, err := codec.decode([]byte(codec.want), c.scale)
+			if err != nil {
+				t.Errorf("Decode%s(%q) raised unexpected error: %q", codec.name, codec.want, err)
+			} else if reverse.Value.Cmp(d.Value) != 0 || reverse.Scale != d.Scale {
+				t.Errorf("Decode%s(%q) == %v, want %v", codec.name, codec.want, reverse, d)
+			}
+		}
+	}
+
+	// Alternate forms accepted on input
+	alternates := []struct {
+		in   string
+		want int64
+		fn   func([]byte, int) (Decimal, error)
+	}{
+		{" 42", 42, DecodeLeadingSeparateNumeric},
+		{"42", 42, DecodeTrailingNumeric},
+		{"4]", -40, DecodeTrailingNumeric},
+		{"4[", 40, DecodeTrailingNumeric},
+	}
+	for _, c := range alternates {
+		if d, err := c.fn([]byte(c.in), 0); err != nil || d.Value.Int64() != c.want {
+			t.Errorf("decoding %q == %v, %v, want %d", c.in, d, err, c.want)
+		}
+	}
+
+	var signErr *DecimalSignError
+	if _, err := DecodeLeadingSeparateNumeric([]byte("*42"), 0); !errors.As(err, &signErr) {
+		t.Errorf("DecodeLeadingSeparateNumeric(*42) == %v, want *DecimalSignError", err)
+	}
+	if _, err := DecodeZonedNumeric([]byte("4\x52"), 0); !errors.As(err, &signErr) {
+		t.Errorf("DecodeZonedNumeric(4\\x52) == %v, want *DecimalSignError", err)
+	}
+	var digitErr *DecimalDigitError
+	if _, err := DecodeTrailingNumeric([]byte("4X2"), 0); !errors.As(err, &digitErr) || digitErr.Offset != 1 {
+		t.Errorf("DecodeTrailingNumeric(4X2) == %v, want *DecimalDigitError at offset 1", err)
+	}
+}
+
+func TestZeroNumericStrings(t *testing.T) {
+	// The zero Decimal has a nil Value
+	encoders := []struct {
+		name   string
+		want   string
+		encode func(Decimal, int) ([]byte, error)
+	}{
+		{"LeadingSeparateNumeric", "+00", EncodeLeadingSeparateNumeric},
+		{"TrailingNumeric", "0{", EncodeTrailingNumeric},
+		{"ZonedNumeric", "00", EncodeZonedNumeric},
+	}
+	for _, e := range encoders {
+		if got, err := e.encode(Decimal{}, 2); err != nil || string(got) != e.want {
+			t.Errorf("Encode%s(Decimal{}, 2) == %q, %v, want %q", e.name, got, err, e.want)
+		}
+	}
+}
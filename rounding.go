@@ -0,0 +1,133 @@
+package vaxdata
+
+import (
+	"math"
+	"math/big"
+)
+
+// roundShift returns the mantissa m shifted right by n bits, rounded by mode
+// for a value with the given sign. n must be less than 64. A carry out of
+// the mantissa is left for the caller's exponent field to absorb.
+func roundShift(m uint64, n uint, neg bool, mode big.RoundingMode) uint64 {
+	if n == 0 {
+		return m
+	}
+
+	q := m >> n
+	rem := m & (1<<n - 1)
+	if rem == 0 {
+		return q
+	}
+
+	half := uint64(1) << (n - 1)
+	var up bool
+	switch mode {
+	case big.ToNearestEven:
+		up = rem > half || (rem == half && (q&1) == 1)
+	case big.ToNearestAway:
+		up = rem >= half
+	case big.AwayFromZero:
+		up = true
+	case big.ToNegativeInf:
+		up = neg
+	case big.ToPositiveInf:
+		up = !neg
+	}
+	if up {
+		q++
+	}
+	return q
+}
+
+// underflowsUp reports whether mode rounds f, which is non-zero and smaller
+// in magnitude than min, away from zero to +-min. Ties go to zero but for
+// big.ToNearestAway.
+func underflowsUp(f, min float64, mode big.RoundingMode) bool {
+	switch mode {
+	case big.ToNearestEven:
+		return math.Abs(f) > min/2
+	case big.ToNearestAway:
+		return math.Abs(f) >= min/2
+	case big.AwayFromZero:
+		return true
+	case big.ToNegativeInf:
+		return math.Signbit(f)
+	case big.ToPositiveInf:
+		return !math.Signbit(f)
+	}
+	return false
+}
+
+// roundFloat64 returns the float64 nearest to x by mode, and the accuracy of
+// the result, accounting for the fewer bits held by subnormals and for
+// overflow. Unlike x.Float64, which always rounds to nearest, ties to even,
+// it honours the directed modes.
+func roundFloat64(x *big.Float, mode big.RoundingMode) (float64, big.Accuracy) {
+	const (
+		mbits = 52    // float64 mantissa bits, less the hidden bit
+		emin  = -1022 // exponent of the smallest normal float64
+		emax  = 1023  // exponent of the largest float64
+	)
+
+	if mode == big.ToNearestEven || x.Sign() == 0 || x.IsInf() {
+		return x.Float64()
+	}
+
+	// x is 0.1m * 2^e, so the float64 exponent of 1.m is e-1
+	p := mbits + 1
+	if e := x.MantExp(nil) - 1; e < emin {
+		p -= emin - e
+	}
+
+	if p <= 0 {
+		// Below the smallest subnormal, which mode either rounds up to
+		// or down to zero. For p == 0, |x| is at least half of it.
+		var up bool
+		switch mode {
+		case big.ToNearestAway:
+			up = p == 0
+		case big.AwayFromZero:
+			up = true
+		case big.ToNegativeInf:
+			up = x.Signbit()
+		case big.ToPositiveInf:
+			up = !x.Signbit()
+		}
+
+		f := 0.0
+		if up {
+			f = math.SmallestNonzeroFloat64
+		}
+		if x.Signbit() {
+			f = -f
+		}
+		return f, big.Accuracy(big.NewFloat(f).Cmp(x))
+	}
+
+	r := new(big.Float).SetMode(mode).SetPrec(uint(p)).Set(x)
+	if r.MantExp(nil) > emax+1 {
+		// At or above 2**1024, which mode either rounds up to Inf or
+		// down to the largest float64
+		up := true
+		switch mode {
+		case big.ToZero:
+			up = false
+		case big.ToNegativeInf:
+			up = x.Signbit()
+		case big.ToPositiveInf:
+			up = !x.Signbit()
+		}
+
+		f := math.MaxFloat64
+		if up {
+			f = math.Inf(1)
+		}
+		if x.Signbit() {
+			f = -f
+		}
+		return f, big.Accuracy(big.NewFloat(f).Cmp(x))
+	}
+
+	f, _ := r.Float64()
+	return f, r.Acc()
+}
@@ -0,0 +1,318 @@
+package vaxdata
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+var roundingModes = []big.RoundingMode{
+	big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf,
+}
+
+// roundModel returns lo or hi, the values of the target format either side of
+// the exact value x, as chosen by mode. loEven reports whether lo has an even
+// last mantissa bit.
+func roundModel(x, lo, hi *big.Float, loEven bool, mode big.RoundingMode) *big.Float {
+	toZero, away := lo, hi
+	if x.Signbit() {
+		toZero, away = hi, lo
+	}
+
+	switch mode {
+	case big.ToZero:
+		return toZero
+	case big.AwayFromZero:
+		return away
+	case big.ToNegativeInf:
+		return lo
+	case big.ToPositiveInf:
+		return hi
+	}
+
+	below := new(big.Float).SetPrec(4096).Sub(x, lo)
+	above := new(big.Float).SetPrec(4096).Sub(hi, x)
+	switch below.Cmp(above) {
+	case -1:
+		return lo
+	case 1:
+		return hi
+	}
+	if mode == big.ToNearestAway {
+		return away
+	}
+	if loEven {
+		return lo
+	}
+	return hi
+}
+
+// checkRounding reports whether got is the float nearest to the exact value
+// x by mode, checking it against its neighbours.
+func checkRounding[F float32 | float64](t *testing.T, desc string, x *big.Float, got F, mode big.RoundingMode) {
+	t.Helper()
+
+	next := func(f F, up bool) F {
+		dir := math.Inf(-1)
+		if up {
+			dir = math.Inf(1)
+		}
+		if g, ok := any(f).(float32); ok {
+			return F(math.Nextafter32(g, float32(dir)))
+		}
+		return F(math.Nextafter(float64(f), dir))
+	}
+	even := func(f F) bool {
+		if g, ok := any(f).(float32); ok {
+			return math.Float32bits(g)&1 == 0
+		}
+		return math.Float64bits(float64(f))&1 == 0
+	}
+
+	// Rounding takes +-Inf as the power of two after the largest value
+	toBig := func(f F) *big.Float {
+		if !math.IsInf(float64(f), 0) {
+			return big.NewFloat(float64(f))
+		}
+		b := big.NewFloat(math.Copysign(1, float64(f)))
+		if _, ok := any(f).(float32); ok {
+			return b.SetMantExp(b, 128)
+		}
+		return b.SetMantExp(b, 1024)
+	}
+
+	g := toBig(got)
+	if g.Cmp(x) == 0 {
+		return
+	}
+	lo, hi := got, next(got, true)
+	if g.Cmp(x) > 0 || math.IsInf(float64(got), 1) {
+		lo, hi = next(got, false), got
+	}
+	blo, bhi := toBig(lo), toBig(hi)
+	if (!math.IsInf(float64(lo), 0) && blo.Cmp(x) >= 0) || (!math.IsInf(float64(hi), 0) && bhi.Cmp(x) <= 0) {
+		t.Errorf("%s with %v == %g, which is not adjacent to %g", desc, mode, got, x)
+		return
+	}
+	if want := roundModel(x, blo, bhi, even(lo), mode); g.Cmp(want) != 0 {
+		t.Errorf("%s with %v == %g, want %g (exact %g)", desc, mode, got, want, x)
+	}
+}
+
+// exactVax returns the exact value of the VAX fields with a fraction of
+// fbits bits and the given exponent bias.
+func exactVax(sign uint, exp int, frac uint64, fbits uint, bias int) *big.Float {
+	x := new(big.Float).SetInt(new(big.Int).SetUint64(1<<fbits | frac))
+	x.SetMantExp(x, exp-bias-int(fbits)-1)
+	if sign != 0 {
+		x.Neg(x)
+	}
+	return x
+}
+
+func TestRoundingModes(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	for _, mode := range roundingModes {
+		c, err := NewConverter(Rounding(mode))
+		if err != nil {
+			t.Fatalf("NewConverter(Rounding(%v)) raised unexpected error: %q", mode, err)
+		}
+
+		for range 2000 {
+			sign := r.UintN(2)
+			buf := make([]byte, 8)
+
+			// F_Float exponents 1 and 2 need S_Float subnormal form
+			fv, _ := VaxFFloatfromFields(sign, 1+r.IntN(3), r.Uint32N(VaxFMantissaMask+1))
+			binary.BigEndian.PutUint32(buf, uint32(fv))
+			f, err := c.Float32fromVaxFFloat(buf[:4])
+			if err != nil {
+				t.Fatalf("Float32fromVaxFFloat(%X) raised unexpected error: %q", buf[:4], err)
+			}
+			checkRounding(t, fmt.Sprintf("%T(%X)", fv, fv), exactVax(fv.Sign(), fv.Exponent(), uint64(fv.Fraction()), 23, 128), f, mode)
+
+			// G_Float exponents 1 and 2 need T_Float subnormal form
+			gv, _ := VaxGFloatfromFields(sign, 1+r.IntN(3), r.Uint64N(1<<52))
+			binary.BigEndian.PutUint64(buf, uint64(gv))
+			g, err := c.Float64fromVaxGFloat(buf)
+			if err != nil {
+				t.Fatalf("Float64fromVaxGFloat(%X) raised unexpected error: %q", buf, err)
+			}
+			checkRounding(t, fmt.Sprintf("%T(%X)", gv, gv), exactVax(gv.Sign(), gv.Exponent(), gv.Fraction(), 52, 1024), g, mode)
+
+			// Every D_Float loses three fraction bits
+			dv, _ := VaxDFloatfromFields(sign, 1+r.IntN(255), r.Uint64N(1<<55))
+			binary.BigEndian.PutUint64(buf, uint64(dv))
+			d, err := c.Float64fromVaxDFloat(buf)
+			if err != nil {
+				t.Fatalf("Float64fromVaxDFloat(%X) raised unexpected error: %q", buf, err)
+			}
+			checkRounding(t, fmt.Sprintf("%T(%X)", dv, dv), exactVax(dv.Sign(), dv.Exponent(), dv.Fraction(), 55, 128), d, mode)
+
+			// H_Float exponents about the T_Float subnormal range, 1.0
+			// and the largest T_Float
+			var hv VaxHFloat
+			for i := range hv {
+				hv[i] = byte(r.Uint32())
+			}
+			exp := 16384 - 1080 + r.IntN(80)
+			switch r.IntN(4) {
+			case 0:
+				exp = 16384 - 10 + r.IntN(20)
+			case 1:
+				exp = 16384 + 1020 + r.IntN(80)
+			}
+			binary.BigEndian.PutUint16(hv[14:16], uint16(sign<<15)|uint16(exp))
+			h, acc, err := c.Float64fromVaxHFloat(hv[:])
+			x, _ := BigFloatfromVaxHFloat(hv[:])
+			switch {
+			case err == nil:
+			case err.(*ConversionError).Err == ErrUnderflow && h == 0:
+			case err.(*ConversionError).Err == ErrOverflow && (math.IsInf(h, 0) || x.MantExp(nil) > 1024):
+			default:
+				t.Fatalf("Float64fromVaxHFloat(%X) raised unexpected error: %q", hv, err)
+			}
+			if err == nil && x.MantExp(nil) > 1024 {
+				t.Errorf("Float64fromVaxHFloat(%X) with %v == %g, want ErrOverflow", hv, mode, h)
+			}
+			checkRounding(t, fmt.Sprintf("%T(%X)", hv, hv), x, h, mode)
+			if want := big.Accuracy(big.NewFloat(h).Cmp(x)); acc != want {
+				t.Errorf("Float64fromVaxHFloat(%X) with %v has accuracy %v, want %v", hv, mode, acc, want)
+			}
+		}
+	}
+}
+
+func TestRoundingToVaxHFloat(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+
+	for _, mode := range roundingModes {
+		c, _ := NewConverter(Rounding(mode))
+
+		for range 2000 {
+			// A value with more bits than an H_Float mantissa
+			m := new(big.Int).SetUint64(r.Uint64())
+			m.Lsh(m, 64).Or(m, new(big.Int).SetUint64(r.Uint64()))
+			m.Lsh(m, 22).Or(m, new(big.Int).SetUint64(r.Uint64N(1<<22)))
+			x := new(big.Float).SetInt(m)
+			x.SetMantExp(x, r.IntN(200)-100)
+			if r.IntN(2) == 0 {
+				x.Neg(x)
+			}
+			if x.Sign() == 0 {
+				continue
+			}
+
+			v, err := c.VaxHFloatfromBigFloat(x)
+			if err != nil {
+				t.Fatalf("VaxHFloatfromBigFloat(%g) raised unexpected error: %q", x, err)
+			}
+			got, _ := BigFloatfromVaxHFloat(v[:])
+
+			// The H_Float values either side of x, from its mantissa
+			// truncated to VaxHFloatPrec bits
+			e := x.MantExp(nil) - VaxHFloatPrec
+			t0, _ := new(big.Float).SetMantExp(x, -e).Int(nil)
+			if tx := new(big.Float).SetInt(t0); tx.SetMantExp(tx, e).Cmp(x) == 0 {
+				if got.Cmp(x) != 0 {
+					t.Errorf("VaxHFloatfromBigFloat(%g) with %v == %g, want exact", x, mode, got)
+				}
+				continue
+			}
+			t1 := new(big.Int).Add(t0, big.NewInt(int64(x.Sign())))
+			lo, hi := t0, t1
+			if x.Signbit() {
+				lo, hi = t1, t0
+			}
+			blo := new(big.Float).SetInt(lo)
+			blo.SetMantExp(blo, e)
+			bhi := new(big.Float).SetInt(hi)
+			bhi.SetMantExp(bhi, e)
+
+			loEven := new(big.Int).Abs(lo).Bit(0) == 0
+			if want := roundModel(x, blo, bhi, loEven, mode); got.Cmp(want) != 0 {
+				t.Errorf("VaxHFloatfromBigFloat(%g) with %v == %g, want %g", x, mode, got, want)
+			}
+		}
+	}
+}
+
+func TestRoundingToVaxFFloat(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+
+	for _, mode := range roundingModes {
+		c, _ := NewConverter(Rounding(mode))
+
+		for range 2000 {
+			// A float64 within the F_Float range, about its smallest
+			// exponents and 1.0
+			f := math.Ldexp(1+r.Float64(), -128+r.IntN(4))
+			if r.IntN(2) == 0 {
+				f = math.Ldexp(1+r.Float64(), r.IntN(8)-4)
+			}
+			if r.IntN(2) == 0 {
+				f = -f
+			}
+
+			got, err := c.VaxFFloatfromFloat64(f)
+			if err != nil {
+				t.Fatalf("VaxFFloatfromFloat64(%g) raised unexpected error: %q", f, err)
+			}
+
+			x := new(big.Float).SetMode(mode).SetPrec(vaxFFloatPrec).SetFloat64(f)
+			sign, exp, frac, _ := vaxFieldsfromBigFloat(x, vaxFFloatPrec, int(VaxFExponentBias), 255)
+			if want, _ := VaxFFloatfromFields(sign, exp, uint32(frac)); got != want {
+				t.Errorf("VaxFFloatfromFloat64(%g) with %v == %08X, want %08X", f, mode, uint32(got), uint32(want))
+			}
+		}
+	}
+}
+
+func TestRoundingUnderflow(t *testing.T) {
+	const tiny = math.SmallestNonzeroFloat32
+
+	var tests = []struct {
+		mode big.RoundingMode
+		f    float32
+		want VaxFFloat
+	}{
+		{big.ToZero, tiny, 0},
+		{big.ToZero, -tiny, 0},
+		{big.AwayFromZero, tiny, 0x00000080},
+		{big.AwayFromZero, -tiny, 0x00008080},
+		{big.ToPositiveInf, tiny, 0x00000080},
+		{big.ToPositiveInf, -tiny, 0},
+		{big.ToNegativeInf, tiny, 0},
+		{big.ToNegativeInf, -tiny, 0x00008080},
+		{big.ToNearestEven, 0x1p-129, 0},
+		{big.ToNearestEven, 0x1.8p-129, 0x00000080},
+		{big.ToNearestAway, 0x1p-129, 0x00000080},
+		{big.ToNearestAway, -0x1p-130, 0},
+	}
+
+	for _, tt := range tests {
+		c, _ := NewConverter(Rounding(tt.mode))
+		if got, err := c.VaxFFloatfromFloat32(tt.f); err != nil || got != tt.want {
+			t.Errorf("VaxFFloatfromFloat32(%g) with %v == %08X, %v, want %08X", tt.f, tt.mode, uint32(got), err, uint32(tt.want))
+		}
+		if got, err := c.VaxFFloatfromFloat64(float64(tt.f)); err != nil || got != tt.want {
+			t.Errorf("VaxFFloatfromFloat64(%g) with %v == %08X, %v, want %08X", tt.f, tt.mode, uint32(got), err, uint32(tt.want))
+		}
+	}
+
+	// The OnUnderflow Action applies to the rounded value
+	c, _ := NewConverter(Rounding(big.ToPositiveInf), OnUnderflow(Report))
+	v, err := c.VaxGFloatfromFloat64(math.SmallestNonzeroFloat64)
+	if !errors.Is(err, ErrUnderflow) || v != 0x0000000000000010 {
+		t.Errorf("VaxGFloatfromFloat64(SmallestNonzeroFloat64) == %016X, %v, want 0000000000000010 with %v", uint64(v), err, ErrUnderflow)
+	}
+	c, _ = NewConverter(Rounding(big.AwayFromZero), OnUnderflow(ToZero))
+	if v, err := c.VaxDFloatfromFloat64(-1e-300); err != nil || v != 0 {
+		t.Errorf("VaxDFloatfromFloat64(-1e-300) == %016X, %v, want 0", uint64(v), err)
+	}
+}
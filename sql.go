@@ -0,0 +1,174 @@
+package vaxdata
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// The VAX floating point types are stored in a database as the bytes of the
+// value as stored on disk, suiting BLOB and bytea columns. Wrap a value in
+// Float64 to store it converted to float64 instead. Scanning accepts either
+// the bytes or a number, and text in the forms taken by UnmarshalText; use
+// Null for columns that may be NULL.
+
+var (
+	_ sql.Scanner   = (*VaxFFloat)(nil)
+	_ driver.Valuer = VaxFFloat(0)
+	_ sql.Scanner   = (*Float64[VaxFFloat])(nil)
+	_ driver.Valuer = Float64[VaxFFloat]{}
+	_ sql.Scanner   = (*Null[VaxFFloat])(nil)
+	_ driver.Valuer = Null[VaxFFloat]{}
+)
+
+// Scan sets v from the F_Float in src as stored on disk, or from a number.
+func (v *VaxFFloat) Scan(src any) error {
+	return scanVax(src, v, func(f float64) error {
+		x, err := VaxFFloatfromFloat64(f)
+		if err == nil {
+			*v = x
+		}
+		return err
+	})
+}
+
+// Value returns the F_Float as stored on disk.
+func (v VaxFFloat) Value() (driver.Value, error) { return v.MarshalBinary() }
+
+// Scan sets v from the D_Float in src as stored on disk, or from a number.
+func (v *VaxDFloat) Scan(src any) error {
+	return scanVax(src, v, func(f float64) error {
+		x, err := VaxDFloatfromFloat64(f)
+		if err == nil {
+			*v = x
+		}
+		return err
+	})
+}
+
+// Value returns the D_Float as stored on disk.
+func (v VaxDFloat) Value() (driver.Value, error) { return v.MarshalBinary() }
+
+// Scan sets v from the G_Float in src as stored on disk, or from a number.
+func (v *VaxGFloat) Scan(src any) error {
+	return scanVax(src, v, func(f float64) error {
+		x, err := VaxGFloatfromFloat64(f)
+		if err == nil {
+			*v = x
+		}
+		return err
+	})
+}
+
+// Value returns the G_Float as stored on disk.
+func (v VaxGFloat) Value() (driver.Value, error) { return v.MarshalBinary() }
+
+// Scan sets v from the H_Float in src as stored on disk, or from a number.
+func (v *VaxHFloat) Scan(src any) error {
+	return scanVax(src, v, func(f float64) error {
+		if math.IsNaN(f) {
+			// big.Float has no NaN; fix up as VaxHFloatfromBigFloat does
+			// for Infinity
+			fixup, _ := VaxHFloatfromBigFloat(new(big.Float).SetInf(math.Signbit(f)))
+			return &ConversionError{"H_Float", true, f, fixup, ErrNotFinite}
+		}
+		x, err := VaxHFloatfromBigFloat(big.NewFloat(f))
+		if err == nil {
+			*v = x
+		}
+		return err
+	})
+}
+
+// Value returns the H_Float as stored on disk.
+func (v VaxHFloat) Value() (driver.Value, error) { return v.MarshalBinary() }
+
+// Float64 holds a VAX floating point value that is stored in a database
+// converted to float64, for REAL and DOUBLE PRECISION columns. Reserved
+// operands, and H_Float's outside the range of a float64, cannot be stored.
+type Float64[T VaxFloat] struct {
+	V T
+}
+
+// Scan sets the value from src as for the wrapped type.
+func (f *Float64[T]) Scan(src any) error {
+	return any(&f.V).(sql.Scanner).Scan(src)
+}
+
+// Value returns the value converted to float64.
+func (f Float64[T]) Value() (driver.Value, error) {
+	switch v := any(f.V).(type) {
+	case VaxFFloat:
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], uint32(v))
+		x, err := Float32fromVaxFFloat(buf[:])
+		return float64(x), err
+	case VaxDFloat:
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		return Float64fromVaxDFloat(buf[:])
+	case VaxGFloat:
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		return Float64fromVaxGFloat(buf[:])
+	case VaxHFloat:
+		x, _, err := Float64fromVaxHFloat(v[:])
+		return x, err
+	}
+	panic("unreachable")
+}
+
+// Null holds a VAX floating point value that may be NULL, in the manner of
+// sql.Null. The value is stored as for the wrapped type.
+type Null[T VaxFloat] struct {
+	V     T
+	Valid bool // Valid is true if V is not NULL
+}
+
+// Scan sets the value from src, which may be NULL.
+func (n *Null[T]) Scan(src any) error {
+	var zero T
+	n.V, n.Valid = zero, false
+	if src == nil {
+		return nil
+	}
+	if err := any(&n.V).(sql.Scanner).Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value returns the value as stored on disk, or nil if it is NULL.
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return any(n.V).(driver.Valuer).Value()
+}
+
+// scanVax sets u from src, which holds the bytes as stored on disk, text or
+// a number, converting numbers with set.
+func scanVax(src any, u interface {
+	encoding.BinaryUnmarshaler
+	encoding.TextUnmarshaler
+}, set func(float64) error) error {
+	switch src := src.(type) {
+	case []byte:
+		return u.UnmarshalBinary(src)
+	case string:
+		return u.UnmarshalText([]byte(src))
+	case float64:
+		return set(src)
+	case int64:
+		return set(float64(src))
+	case nil:
+		return errors.New("cannot scan NULL into a VAX float; use vaxdata.Null")
+	}
+	return fmt.Errorf("cannot scan %T into a VAX float", src)
+}
@@ -0,0 +1,141 @@
+package vaxdata
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+// fakeDriver is a database/sql driver holding a single column table. Every
+// Exec appends its argument as a row, and every Query returns the rows.
+type fakeDriver struct {
+	rows []driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConnector struct{ d *fakeDriver }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn(c), nil }
+func (c fakeConnector) Driver() driver.Driver                        { return c.d }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt(c), nil }
+func (fakeConn) Close() error                          { return nil }
+func (fakeConn) Begin() (driver.Tx, error)             { return nil, errors.New("no transactions") }
+
+type fakeStmt struct{ d *fakeDriver }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.rows = append(s.d.rows, args[0])
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{rows: s.d.rows}, nil
+}
+
+type fakeRows struct{ rows []driver.Value }
+
+func (*fakeRows) Columns() []string { return []string{"v"} }
+func (*fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	dest[0], r.rows = r.rows[0], r.rows[1:]
+	return nil
+}
+
+func TestVaxFloatSQL(t *testing.T) {
+	d := new(fakeDriver)
+	db := sql.OpenDB(fakeConnector{d})
+	defer db.Close()
+
+	args := []any{
+		VaxFFloat(0x0FD04149),
+		VaxGFloat(0x2D18544421FB4029),
+		Float64[VaxFFloat]{0x0FD04149},
+		Null[VaxGFloat]{},
+		Null[VaxFFloat]{0x00000001, true},
+	}
+	want := []driver.Value{
+		[]byte{0x0F, 0xD0, 0x41, 0x49},
+		[]byte{0x2D, 0x18, 0x54, 0x44, 0x21, 0xFB, 0x40, 0x29},
+		float64(float32(3.141590)),
+		nil,
+		[]byte{0x00, 0x00, 0x00, 0x01},
+	}
+	for i, arg := range args {
+		if _, err := db.Exec("INSERT", arg); err != nil {
+			t.Fatalf("Exec(%v) raised unexpected error: %q", arg, err)
+		}
+		if got, ok := d.rows[i].([]byte); ok && !bytes.Equal(got, want[i].([]byte)) || !ok && d.rows[i] != want[i] {
+			t.Errorf("Exec(%T(%v)) stored %#v, want %#v", arg, arg, d.rows[i], want[i])
+		}
+	}
+	if _, err := db.Exec("INSERT", Float64[VaxFFloat]{0x00008000}); err == nil {
+		t.Errorf("Exec of a reserved operand as float64 did not raise an error")
+	}
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("Query raised unexpected error: %q", err)
+	}
+	defer rows.Close()
+
+	var (
+		f  VaxFFloat
+		g  VaxGFloat
+		ff Float64[VaxFFloat]
+		ng Null[VaxGFloat]
+		nf Null[VaxFFloat]
+	)
+	for _, dest := range []any{&f, &g, &ff, &ng, &nf} {
+		if !rows.Next() {
+			t.Fatalf("Query returned too few rows: %v", rows.Err())
+		}
+		if err := rows.Scan(dest); err != nil {
+			t.Errorf("Scan(%T) raised unexpected error: %q", dest, err)
+		}
+	}
+	if f != args[0] || g != args[1] || ff != args[2] || ng != args[3] || nf != args[4] {
+		t.Errorf("Scan == %v, %v, %v, %v, %v, want %v", f, g, ff, ng, nf, args)
+	}
+}
+
+func TestVaxFloatScanErrors(t *testing.T) {
+	var tests = []struct {
+		dest sql.Scanner
+		src  any
+	}{
+		{new(VaxFFloat), nil},
+		{new(VaxFFloat), []byte{0x0F, 0xD0, 0x41}},
+		{new(VaxFFloat), float64(1e39)},
+		{new(VaxGFloat), true},
+		{new(Null[VaxGFloat]), "pi"},
+		{new(VaxHFloat), math.NaN()},
+		{new(Float64[VaxHFloat]), math.NaN()},
+		{new(VaxFFloat), math.NaN()},
+	}
+
+	for _, tt := range tests {
+		err := tt.dest.Scan(tt.src)
+		if err == nil {
+			t.Errorf("%T.Scan(%#v) did not raise an error", tt.dest, tt.src)
+		}
+		if f, ok := tt.src.(float64); ok && math.IsNaN(f) && !errors.Is(err, ErrNotFinite) {
+			t.Errorf("%T.Scan(NaN) raised %v, want %v", tt.dest, err, ErrNotFinite)
+		}
+	}
+}
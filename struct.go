@@ -0,0 +1,663 @@
+package vaxdata
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Structured VAX records are described with Go structs whose fields carry a
+// vax tag naming the VAX data type of the field:
+//
+// 	f    F_Float      float32, float64, complex64, complex128 or VaxFFloat
+// 	d    D_Float      float64, complex128 or VaxDFloat
+// 	g    G_Float      float64, complex128 or VaxGFloat
+// 	h    H_Float      big.Float, *big.Float or VaxHFloat
+// 	b    BYTE         any integer or bool (LOGICAL*1)
+// 	w    WORD         any integer or bool (LOGICAL*2)
+// 	l    LONGWORD     any integer or bool (LOGICAL*4)
+// 	q    QUADWORD     any integer or bool (LOGICAL*8)
+// 	o    OCTAWORD     VaxOctaword
+// 	char CHARACTER*n  string
+//
+// The char tag takes the length of the field, as in vax:"char,32", followed
+// by any of these options:
+//
+// 	notrim        keep trailing padding when decoding
+// 	pad=space     pad with blanks, the default
+// 	pad=nul       pad with NUL's
+// 	charset=name  a character set added with RegisterCharset; the default
+// 	              is dec-mcs, with ascii and latin1 also available
+//
+// Text too long for a char field is truncated and reported as an error.
+// Complex fields hold the complex form of the named floating point type.
+// The tag of an array or slice field applies to each element. Fields may be
+// left untagged when their type implies the VAX data type: sized integers,
+// float32, complex64, struct fields and the VaxFFloat family of types. A
+// tag of "-" excludes a field from the record, as are unexported fields,
+// while fields named _ are skipped over, leaving the field unchanged.
+
+// FieldError reports the field of a structured VAX record that could not be
+// converted.
+type FieldError struct {
+	Path   string // path to the field, such as "Header.Samples[3]"
+	Offset int64  // offset of the field within the record
+	Err    error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s at offset %d: %v", e.Path, e.Offset, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// vaxKind is the VAX data type of a single value in a structured record.
+type vaxKind int
+
+const (
+	kindInvalid vaxKind = iota
+	kindByte
+	kindWord
+	kindLongword
+	kindQuadword
+	kindOctaword
+	kindFFloat
+	kindDFloat
+	kindGFloat
+	kindHFloat
+	kindCharacter
+)
+
+// kindTags maps vax tags to the VAX data type they name.
+var kindTags = map[string]vaxKind{
+	"b": kindByte,
+	"w": kindWord,
+	"l": kindLongword,
+	"q": kindQuadword,
+	"o": kindOctaword,
+	"f": kindFFloat,
+	"d": kindDFloat,
+	"g": kindGFloat,
+	"h": kindHFloat,
+}
+
+// size returns the number of bytes in a value of the kind.
+func (k vaxKind) size() int {
+	switch k {
+	case kindByte:
+		return 1
+	case kindWord:
+		return 2
+	case kindLongword, kindFFloat:
+		return 4
+	case kindQuadword, kindDFloat, kindGFloat:
+		return 8
+	case kindOctaword, kindHFloat:
+		return 16
+	}
+	return 0
+}
+
+var (
+	vaxFFloatType   = reflect.TypeFor[VaxFFloat]()
+	vaxDFloatType   = reflect.TypeFor[VaxDFloat]()
+	vaxGFloatType   = reflect.TypeFor[VaxGFloat]()
+	vaxHFloatType   = reflect.TypeFor[VaxHFloat]()
+	vaxOctawordType = reflect.TypeFor[VaxOctaword]()
+	bigFloatType    = reflect.TypeFor[big.Float]()
+	bigFloatPtrType = reflect.TypeFor[*big.Float]()
+)
+
+// fixedKinds maps the types whose VAX data type is fixed.
+var fixedKinds = map[reflect.Type]vaxKind{
+	vaxFFloatType:   kindFFloat,
+	vaxDFloatType:   kindDFloat,
+	vaxGFloatType:   kindGFloat,
+	vaxHFloatType:   kindHFloat,
+	vaxOctawordType: kindOctaword,
+	bigFloatType:    kindHFloat,
+	bigFloatPtrType: kindHFloat,
+}
+
+// fieldTag is a parsed vax struct tag.
+type fieldTag struct {
+	kind string
+	opts []string
+	char characterField // options of a char tag
+	err  error          // from parsing the options of a char tag
+}
+
+// fieldTags caches the vax struct tags parsed without error.
+var fieldTags sync.Map
+
+func parseTag(tag string) fieldTag {
+	if t, ok := fieldTags.Load(tag); ok {
+		return t.(fieldTag)
+	}
+
+	kind, opts, _ := strings.Cut(tag, ",")
+	t := fieldTag{kind: kind}
+	if opts != "" {
+		t.opts = strings.Split(opts, ",")
+	}
+	if kind == "char" {
+		t.char, t.err = parseCharacterField(t.opts)
+	}
+	if t.err == nil {
+		fieldTags.Store(tag, t)
+	}
+	return t
+}
+
+// leafKind returns the VAX data type of a non-composite value of type t with
+// the given tag.
+func leafKind(t reflect.Type, tag fieldTag) (vaxKind, error) {
+	if tag.kind == "char" {
+		if t.Kind() != reflect.String {
+			return kindInvalid, fmt.Errorf("vax tag %q does not apply to %v", tag.kind, t)
+		}
+		if tag.err != nil {
+			return kindInvalid, tag.err
+		}
+		return kindCharacter, nil
+	}
+
+	if len(tag.opts) > 0 {
+		return kindInvalid, fmt.Errorf("unknown vax tag options %q", strings.Join(tag.opts, ","))
+	}
+
+	kind, ok := kindTags[tag.kind]
+	if tag.kind != "" && !ok {
+		return kindInvalid, fmt.Errorf("unknown vax tag %q", tag.kind)
+	}
+
+	if k, ok := fixedKinds[t]; ok {
+		if tag.kind != "" && kind != k {
+			return kindInvalid, fmt.Errorf("vax tag %q does not apply to %v", tag.kind, t)
+		}
+		return k, nil
+	}
+
+	var implied vaxKind
+	switch t.Kind() {
+	case reflect.Bool:
+		implied = kindLongword
+	case reflect.Int8, reflect.Uint8:
+		implied = kindByte
+	case reflect.Int16, reflect.Uint16:
+		implied = kindWord
+	case reflect.Int32, reflect.Uint32:
+		implied = kindLongword
+	case reflect.Int64, reflect.Uint64:
+		implied = kindQuadword
+	case reflect.Float32, reflect.Complex64:
+		implied = kindFFloat
+	}
+
+	if tag.kind == "" {
+		if implied == kindInvalid {
+			return kindInvalid, fmt.Errorf("%v field needs a vax tag", t)
+		}
+		return implied, nil
+	}
+
+	valid := false
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		valid = kind >= kindByte && kind <= kindQuadword
+	case reflect.Float32, reflect.Complex64:
+		valid = kind == kindFFloat
+	case reflect.Float64, reflect.Complex128:
+		valid = kind == kindFFloat || kind == kindDFloat || kind == kindGFloat
+	}
+	if !valid {
+		return kindInvalid, fmt.Errorf("vax tag %q does not apply to %v", tag.kind, t)
+	}
+	return kind, nil
+}
+
+// recordSize returns the size in bytes of the VAX representation of v.
+func recordSize(v reflect.Value, tag fieldTag, path string) (int, error) {
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+		if v.Type() == vaxHFloatType {
+			break
+		}
+		size := 0
+		for i := 0; i < v.Len(); i++ {
+			n, err := recordSize(v.Index(i), tag, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return 0, err
+			}
+			size += n
+		}
+		return size, nil
+	case reflect.Struct:
+		if v.Type() == vaxOctawordType || v.Type() == bigFloatType {
+			break
+		}
+		if tag.kind != "" {
+			return 0, &FieldError{Path: path, Err: fmt.Errorf("vax tag %q does not apply to %v", tag.kind, v.Type())}
+		}
+		size := 0
+		for _, f := range structFields(v) {
+			n, err := recordSize(f.value, f.tag, joinPath(path, f.name))
+			if err != nil {
+				return 0, err
+			}
+			size += n
+		}
+		return size, nil
+	}
+
+	if _, err := leafKind(v.Type(), tag); err != nil {
+		return 0, &FieldError{Path: path, Err: err}
+	}
+	_, size := leafSize(v, tag)
+	return size, nil
+}
+
+// structField is a field of a struct taking part in a VAX record.
+type structField struct {
+	name  string
+	value reflect.Value
+	tag   fieldTag
+	blank bool
+}
+
+// structFields returns the fields of the struct v which take part in a VAX
+// record, in order.
+func structFields(v reflect.Value) []structField {
+	t := v.Type()
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("vax")
+		if ok && tag == "-" {
+			continue
+		}
+		if !f.IsExported() && f.Name != "_" {
+			continue
+		}
+		fields = append(fields, structField{
+			name:  f.Name,
+			value: v.Field(i),
+			tag:   parseTag(tag),
+			blank: f.Name == "_",
+		})
+	}
+	return fields
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// codec converts a VAX record held in buf.
+type codec struct {
+	buf    []byte
+	offset int
+	err    error // first conversion fault
+}
+
+// fault records the first conversion fault raised by the record.
+func (c *codec) fault(path string, offset int, err error) {
+	if err != nil && c.err == nil {
+		c.err = &FieldError{Path: path, Offset: int64(offset), Err: err}
+	}
+}
+
+// next returns the bytes of the next size byte value in the record and its
+// offset.
+func (c *codec) next(size int) ([]byte, int) {
+	offset := c.offset
+	c.offset += size
+	return c.buf[offset:c.offset], offset
+}
+
+// leafSize returns the size of the non-composite value v, which recordSize
+// has already validated.
+func leafSize(v reflect.Value, tag fieldTag) (vaxKind, int) {
+	kind, _ := leafKind(v.Type(), tag)
+	if kind == kindCharacter {
+		return kind, tag.char.size
+	}
+	if v.Kind() == reflect.Complex64 || v.Kind() == reflect.Complex128 {
+		return kind, 2 * kind.size()
+	}
+	return kind, kind.size()
+}
+
+// decode stores the next value in the record in v, unless skip is set.
+func (c *codec) decode(v reflect.Value, tag fieldTag, path string, skip bool) {
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+		if v.Type() == vaxHFloatType {
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			c.decode(v.Index(i), tag, fmt.Sprintf("%s[%d]", path, i), skip)
+		}
+		return
+	case reflect.Struct:
+		if v.Type() == vaxOctawordType || v.Type() == bigFloatType {
+			break
+		}
+		for _, f := range structFields(v) {
+			c.decode(f.value, f.tag, joinPath(path, f.name), skip || f.blank)
+		}
+		return
+	}
+
+	kind, size := leafSize(v, tag)
+	b, offset := c.next(size)
+	if skip {
+		return
+	}
+
+	switch kind {
+	case kindByte, kindWord, kindLongword, kindQuadword:
+		var raw uint64
+		switch kind {
+		case kindByte:
+			raw = uint64(b[0])
+		case kindWord:
+			raw = uint64(binary.LittleEndian.Uint16(b))
+		case kindLongword:
+			raw = uint64(binary.LittleEndian.Uint32(b))
+		case kindQuadword:
+			raw = binary.LittleEndian.Uint64(b)
+		}
+		c.fault(path, offset, setInteger(v, raw, 8*size))
+	case kindOctaword:
+		v.Set(reflect.ValueOf(VaxOctaword{
+			Lo: binary.LittleEndian.Uint64(b[0:8]),
+			Hi: binary.LittleEndian.Uint64(b[8:16]),
+		}))
+	case kindCharacter:
+		text, err := tag.char.decode(b)
+		c.fault(path, offset, err)
+		v.SetString(text)
+	case kindHFloat:
+		if v.Type() == vaxHFloatType {
+			reflect.Copy(v, reflect.ValueOf(b))
+			return
+		}
+		f, err := BigFloatfromVaxHFloat(b)
+		c.fault(path, offset, err)
+		if v.Type() == bigFloatPtrType {
+			v.Set(reflect.ValueOf(f))
+		} else {
+			v.Addr().Interface().(*big.Float).Set(f)
+		}
+	default:
+		switch v.Type() {
+		case vaxFFloatType:
+			v.SetUint(uint64(binary.BigEndian.Uint32(b)))
+			return
+		case vaxDFloatType, vaxGFloatType:
+			v.SetUint(binary.BigEndian.Uint64(b))
+			return
+		}
+
+		if v.Kind() == reflect.Complex64 || v.Kind() == reflect.Complex128 {
+			half := size / 2
+			re, err := decodeFloat(kind, b[:half])
+			c.fault(path+".real", offset, err)
+			im, err := decodeFloat(kind, b[half:])
+			c.fault(path+".imag", offset+half, err)
+			v.SetComplex(complex(re, im))
+			return
+		}
+
+		f, err := decodeFloat(kind, b)
+		c.fault(path, offset, err)
+		v.SetFloat(f)
+	}
+}
+
+// decodeFloat converts a VAX floating point value of the given kind.
+func decodeFloat(kind vaxKind, b []byte) (float64, error) {
+	switch kind {
+	case kindFFloat:
+		f, err := Float32fromVaxFFloat(b)
+		return float64(f), err
+	case kindDFloat:
+		return Float64fromVaxDFloat(b)
+	default:
+		return Float64fromVaxGFloat(b)
+	}
+}
+
+// setInteger stores the bits-wide VAX integer raw in v, sign extending it
+// for signed fields.
+func setInteger(v reflect.Value, raw uint64, bits int) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		// VAX LOGICAL values are true when the low-order bit is set
+		v.SetBool(raw&1 == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x := int64(raw<<(64-bits)) >> (64 - bits)
+		if v.OverflowInt(x) {
+			return fmt.Errorf("%d overflows %v", x, v.Type())
+		}
+		v.SetInt(x)
+	default:
+		if v.OverflowUint(raw) {
+			return fmt.Errorf("%d overflows %v", raw, v.Type())
+		}
+		v.SetUint(raw)
+	}
+	return nil
+}
+
+// Read reads structured VAX data from r into data, in the manner of
+// binary.Read. Data must be a pointer to a value of fixed size, or a slice
+// of such values, described by vax struct tags.
+//
+// As with the single value conversions every field is converted, with faults
+// fixed up, and the first fault is returned as a *FieldError naming the
+// field and its offset within the record. An error reading from r is
+// returned as is, io.EOF only if no bytes were read.
+func Read(r io.Reader, data any) error {
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return errors.New("vaxdata.Read of nil pointer")
+		}
+		v = v.Elem()
+	case reflect.Slice:
+	default:
+		return fmt.Errorf("vaxdata.Read of non-pointer %T", data)
+	}
+
+	size, err := recordSize(v, fieldTag{}, "")
+	if err != nil {
+		return err
+	}
+
+	c := &codec{buf: make([]byte, size)}
+	if _, err := io.ReadFull(r, c.buf); err != nil {
+		return err
+	}
+
+	c.decode(v, fieldTag{}, "", false)
+	return c.err
+}
+
+// encode stores v as the next value in the record. Blank fields are zeroed.
+func (c *codec) encode(v reflect.Value, tag fieldTag, path string, skip bool) {
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+		if v.Type() == vaxHFloatType {
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			c.encode(v.Index(i), tag, fmt.Sprintf("%s[%d]", path, i), skip)
+		}
+		return
+	case reflect.Struct:
+		if v.Type() == vaxOctawordType || v.Type() == bigFloatType {
+			break
+		}
+		for _, f := range structFields(v) {
+			c.encode(f.value, f.tag, joinPath(path, f.name), skip || f.blank)
+		}
+		return
+	}
+
+	kind, size := leafSize(v, tag)
+	b, offset := c.next(size)
+	if skip {
+		return
+	}
+
+	switch kind {
+	case kindByte, kindWord, kindLongword, kindQuadword:
+		raw, err := integerBits(v, 8*size)
+		c.fault(path, offset, err)
+		switch kind {
+		case kindByte:
+			b[0] = byte(raw)
+		case kindWord:
+			binary.LittleEndian.PutUint16(b, uint16(raw))
+		case kindLongword:
+			binary.LittleEndian.PutUint32(b, uint32(raw))
+		case kindQuadword:
+			binary.LittleEndian.PutUint64(b, raw)
+		}
+	case kindOctaword:
+		o := v.Interface().(VaxOctaword)
+		binary.LittleEndian.PutUint64(b[0:8], o.Lo)
+		binary.LittleEndian.PutUint64(b[8:16], o.Hi)
+	case kindCharacter:
+		c.fault(path, offset, tag.char.encode(b, v.String()))
+	case kindHFloat:
+		if v.Type() == vaxHFloatType {
+			reflect.Copy(reflect.ValueOf(b), v)
+			return
+		}
+		var f *big.Float
+		if v.Type() == bigFloatPtrType {
+			f = v.Interface().(*big.Float)
+		} else if v.CanAddr() {
+			f = v.Addr().Interface().(*big.Float)
+		} else {
+			x := v.Interface().(big.Float)
+			f = &x
+		}
+		if f == nil {
+			f = new(big.Float)
+		}
+		h, err := VaxHFloatfromBigFloat(f)
+		c.fault(path, offset, err)
+		copy(b, h[:])
+	default:
+		switch v.Type() {
+		case vaxFFloatType:
+			binary.BigEndian.PutUint32(b, uint32(v.Uint()))
+			return
+		case vaxDFloatType, vaxGFloatType:
+			binary.BigEndian.PutUint64(b, v.Uint())
+			return
+		}
+
+		if v.Kind() == reflect.Complex64 || v.Kind() == reflect.Complex128 {
+			half := size / 2
+			z := v.Complex()
+			c.fault(path+".real", offset, encodeFloat(kind, b[:half], real(z)))
+			c.fault(path+".imag", offset+half, encodeFloat(kind, b[half:], imag(z)))
+			return
+		}
+
+		c.fault(path, offset, encodeFloat(kind, b, v.Float()))
+	}
+}
+
+// encodeFloat stores f in b as a VAX floating point value of the given kind.
+// The fixup value is stored on error.
+func encodeFloat(kind vaxKind, b []byte, f float64) error {
+	switch kind {
+	case kindFFloat:
+		v, err := VaxFFloatfromFloat64(f)
+		binary.BigEndian.PutUint32(b, uint32(v))
+		return err
+	case kindDFloat:
+		v, err := VaxDFloatfromFloat64(f)
+		binary.BigEndian.PutUint64(b, uint64(v))
+		return err
+	default:
+		v, err := VaxGFloatfromFloat64(f)
+		binary.BigEndian.PutUint64(b, uint64(v))
+		return err
+	}
+}
+
+// integerBits returns v as a bits-wide VAX integer. Values outside the range
+// of the VAX integer are saturated to its extrema.
+func integerBits(v reflect.Value, bits int) (uint64, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		// VAX FORTRAN .TRUE. has every bit set
+		if v.Bool() {
+			return math.MaxUint64, nil
+		}
+		return 0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x := v.Int()
+		lo, hi := int64(-1)<<(bits-1), int64(uint64(1)<<(bits-1)-1)
+		if x < lo {
+			return uint64(lo), fmt.Errorf("%d too small for a %d-bit VAX integer", x, bits)
+		} else if x > hi {
+			return uint64(hi), fmt.Errorf("%d too large for a %d-bit VAX integer", x, bits)
+		}
+		return uint64(x), nil
+	default:
+		x := v.Uint()
+		hi := uint64(math.MaxUint64) >> (64 - bits)
+		if x > hi {
+			return hi, fmt.Errorf("%d too large for a %d-bit VAX integer", x, bits)
+		}
+		return x, nil
+	}
+}
+
+// Write writes the VAX representation of data to w, in the manner of
+// binary.Write. Data must be a value of fixed size, a pointer to one, or a
+// slice of such values, described by vax struct tags.
+//
+// Every field is written, with the fixup value in place of any that cannot
+// be converted, so the record is always complete. The first fault is
+// returned as a *FieldError naming the field and its offset within the
+// record.
+func Write(w io.Writer, data any) error {
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if !v.IsValid() {
+		return fmt.Errorf("vaxdata.Write of invalid value %T", data)
+	}
+
+	size, err := recordSize(v, fieldTag{}, "")
+	if err != nil {
+		return err
+	}
+
+	c := &codec{buf: make([]byte, size)}
+	c.encode(v, fieldTag{}, "", false)
+	if _, err := w.Write(c.buf); err != nil {
+		return err
+	}
+	return c.err
+}
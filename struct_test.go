@@ -0,0 +1,170 @@
+package vaxdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+type testPoint struct {
+	X, Y float32
+}
+
+type testRecord struct {
+	ID      int32
+	Flag    bool    `vax:"w"`
+	Count   int     `vax:"w"`
+	Scale   float64 `vax:"d"`
+	Origin  testPoint
+	Samples [2]float64 `vax:"g"`
+	_       [2]byte
+	Z       complex64
+	Raw     VaxFFloat
+	Ignored string `vax:"-"`
+}
+
+var testRecordBytes = []byte{
+	0xFE, 0xFF, 0xFF, 0xFF, // ID
+	0xFF, 0xFF, // Flag
+	0x34, 0x12, // Count
+	0x68, 0xC0, 0xA2, 0x21, 0x0F, 0xDA, 0x41, 0x49, // Scale
+	0x00, 0x00, 0x40, 0x80, 0x00, 0x00, 0x41, 0x60, // Origin
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0x10, // Samples[0]
+	0x2D, 0x18, 0x54, 0x44, 0x21, 0xFB, 0xC0, 0x29, // Samples[1]
+	0xAA, 0xBB, // _
+	0x00, 0x00, 0x40, 0x80, 0x00, 0x00, 0xC0, 0x80, // Z
+	0x0F, 0xD0, 0x41, 0x49, // Raw
+}
+
+func TestRead(t *testing.T) {
+	var got testRecord
+	if err := Read(bytes.NewReader(testRecordBytes), &got); err != nil {
+		t.Fatalf("Read raised unexpected error: %q", err)
+	}
+
+	want := testRecord{
+		ID:      -2,
+		Flag:    true,
+		Count:   0x1234,
+		Scale:   math.Pi,
+		Origin:  testPoint{1, 3.5},
+		Samples: [2]float64{1, -math.Pi},
+		Z:       complex(1, -1),
+		Raw:     0x0FD04149,
+	}
+	if got != want {
+		t.Errorf("Read(%X) == %+v, want %+v", testRecordBytes, got, want)
+	}
+
+	// A slice of records
+	records := make([]testPoint, 2)
+	in := []byte{
+		0x00, 0x00, 0x40, 0x80, 0x00, 0x00, 0x41, 0x60,
+		0x00, 0x00, 0x41, 0x60, 0x00, 0x00, 0x40, 0x80,
+	}
+	if err := Read(bytes.NewReader(in), records); err != nil {
+		t.Errorf("Read raised unexpected error: %q", err)
+	} else if records[0] != (testPoint{1, 3.5}) || records[1] != (testPoint{3.5, 1}) {
+		t.Errorf("Read(%X) == %+v", in, records)
+	}
+}
+
+func TestReadFieldError(t *testing.T) {
+	in := append([]byte(nil), testRecordBytes...)
+	copy(in[32:40], []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80, 0x00}) // Samples[1]
+
+	var got testRecord
+	err := Read(bytes.NewReader(in), &got)
+	var ferr *FieldError
+	if !errors.As(err, &ferr) {
+		t.Fatalf("Read(%X) == %v, want a *FieldError", in, err)
+	}
+	if ferr.Path != "Samples[1]" || ferr.Offset != 32 {
+		t.Errorf("Read(%X) == %q, want Samples[1] at offset 32", in, err)
+	}
+	if got.Z != complex(1, -1) {
+		t.Errorf("Read(%X) stopped at the first fault: %+v", in, got)
+	}
+
+	// Structural errors are reported before reading
+	var bad struct {
+		A int32
+		B float64
+	}
+	err = Read(bytes.NewReader(in), &bad)
+	if !errors.As(err, &ferr) || ferr.Path != "B" {
+		t.Errorf("Read of an untagged float64 == %v, want a *FieldError for B", err)
+	}
+
+	if err := Read(bytes.NewReader(in[:10]), &got); err != io.ErrUnexpectedEOF {
+		t.Errorf("Read of a short record == %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	in := testRecord{
+		ID:      -2,
+		Flag:    true,
+		Count:   0x1234,
+		Scale:   math.Pi,
+		Origin:  testPoint{1, 3.5},
+		Samples: [2]float64{1, -math.Pi},
+		Z:       complex(1, -1),
+		Raw:     0x0FD04149,
+		Ignored: "not written",
+	}
+
+	// Blank fields are written as zeros
+	want := append([]byte(nil), testRecordBytes...)
+	want[40], want[41] = 0, 0
+
+	var buf bytes.Buffer
+	if err := Write(&buf, &in); err != nil {
+		t.Errorf("Write raised unexpected error: %q", err)
+	} else if !sliceByteEquals(buf.Bytes(), want) {
+		t.Errorf("Write(%+v) == %X, want %X", in, buf.Bytes(), want)
+	}
+
+	// Values are accepted as well as pointers
+	buf.Reset()
+	if err := Write(&buf, in); err != nil || !sliceByteEquals(buf.Bytes(), want) {
+		t.Errorf("Write(%+v) == %X, %v, want %X", in, buf.Bytes(), err, want)
+	}
+}
+
+func TestWriteFieldError(t *testing.T) {
+	in := testRecord{Samples: [2]float64{1, math.NaN()}, Count: 70000}
+
+	var buf bytes.Buffer
+	err := Write(&buf, &in)
+	var ferr *FieldError
+	if !errors.As(err, &ferr) {
+		t.Fatalf("Write(%+v) == %v, want a *FieldError", in, err)
+	}
+	if ferr.Path != "Count" || ferr.Offset != 6 {
+		t.Errorf("Write(%+v) == %q, want Count at offset 6", in, err)
+	}
+	if buf.Len() != len(testRecordBytes) {
+		t.Fatalf("Write(%+v) wrote %d bytes, want %d", in, buf.Len(), len(testRecordBytes))
+	}
+
+	// The overflowing WORD is saturated and the NaN fixed up
+	var out testRecord
+	Read(bytes.NewReader(buf.Bytes()), &out)
+	if out.Count != math.MaxInt16 {
+		t.Errorf("Write(%+v) stored Count %d, want %d", in, out.Count, math.MaxInt16)
+	}
+	b := buf.Bytes()[32:40]
+	if v, _ := VaxGFloatfromFloat64(math.NaN()); !sliceByteEquals(b, binary.BigEndian.AppendUint64(nil, uint64(v))) {
+		t.Errorf("Write(%+v) stored Samples[1] as %X, want the NaN fixup", in, b)
+	}
+
+	in = testRecord{Z: complex(float32(math.Inf(1)), 0)}
+	err = Write(&buf, &in)
+	if !errors.As(err, &ferr) || ferr.Path != "Z.real" {
+		t.Errorf("Write(%+v) == %v, want a *FieldError for Z.real", in, err)
+	}
+}
@@ -0,0 +1,372 @@
+package vaxdata
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+)
+
+// The VAX floating point types marshal to text and JSON as decimal numbers,
+// using the fewest digits that convert back to the same value. Reserved
+// operands have no value, so they marshal instead in hex form: "0x" followed
+// by the bytes as stored on disk, as a string in JSON. Dirty zeros marshal as
+// 0; wrap a value in Hex to carry its bits exactly. Unmarshaling accepts
+// either form, and a JSON string holding either.
+//
+// F_Float, D_Float and G_Float values are formatted from their exact value
+// with big.Float, as H_Float's are. A float64 lacks the last three bits of a
+// D_Float mantissa, and IEEE subnormals lack the low bits of the F_Float and
+// G_Float values with the smallest exponents.
+
+var (
+	_ encoding.TextAppender    = VaxFFloat(0)
+	_ encoding.TextUnmarshaler = (*VaxFFloat)(nil)
+	_ json.Marshaler           = VaxFFloat(0)
+	_ json.Unmarshaler         = (*VaxFFloat)(nil)
+	_ encoding.TextAppender    = Hex[VaxFFloat]{}
+	_ json.Unmarshaler         = (*Hex[VaxFFloat])(nil)
+)
+
+// AppendText appends the F_Float to b as a decimal number.
+func (v VaxFFloat) AppendText(b []byte) ([]byte, error) {
+	if v.IsReservedOperand() {
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], uint32(v))
+		return appendHex(b, buf[:]), nil
+	}
+	return v.bigFloat().Append(b, 'g', -1), nil
+}
+
+// MarshalText returns the F_Float as a decimal number.
+func (v VaxFFloat) MarshalText() ([]byte, error) { return v.AppendText(nil) }
+
+// UnmarshalText sets v to the F_Float nearest the decimal number in text, or
+// to the F_Float in hex form.
+func (v *VaxFFloat) UnmarshalText(text []byte) error {
+	if isHex(text) {
+		return unmarshalHex(text, v)
+	}
+	f, _, err := big.ParseFloat(string(text), 10, vaxFFloatPrec, big.ToNearestEven)
+	if err != nil {
+		return err
+	}
+	x, err := vaxFFloatfromBigFloat(f)
+	if err != nil {
+		return err
+	}
+	*v = x
+	return nil
+}
+
+// vaxFFloatPrec is the precision, in bits, of an F_Float mantissa including
+// the hidden bit.
+const vaxFFloatPrec = 24
+
+// bigFloat returns the exact value of the F_Float, which is not a reserved
+// operand, with a precision of vaxFFloatPrec.
+func (v VaxFFloat) bigFloat() *big.Float {
+	return vaxBigFloat(v.Sign(), v.Exponent(), uint64(v.Fraction()), vaxFFloatPrec, int(VaxFExponentBias))
+}
+
+// vaxFFloatfromBigFloat returns the F_Float nearest to x. Values outside the
+// F_Float range convert as their float32 does.
+func vaxFFloatfromBigFloat(x *big.Float) (VaxFFloat, error) {
+	sign, exp, frac, ok := vaxFieldsfromBigFloat(x, vaxFFloatPrec, int(VaxFExponentBias), int(VaxFExponentMask>>VaxFMantissaSize))
+	if !ok {
+		f, _ := x.Float32()
+		return VaxFFloatfromFloat32(f)
+	}
+	return VaxFFloatfromFields(sign, exp, uint32(frac))
+}
+
+// MarshalJSON returns the F_Float as a JSON number.
+func (v VaxFFloat) MarshalJSON() ([]byte, error) { return marshalJSON(v) }
+
+// UnmarshalJSON sets v to the F_Float in a JSON number or string.
+func (v *VaxFFloat) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// AppendText appends the D_Float to b as a decimal number.
+func (v VaxDFloat) AppendText(b []byte) ([]byte, error) {
+	if v.IsReservedOperand() {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		return appendHex(b, buf[:]), nil
+	}
+	return v.bigFloat().Append(b, 'g', -1), nil
+}
+
+// MarshalText returns the D_Float as a decimal number.
+func (v VaxDFloat) MarshalText() ([]byte, error) { return v.AppendText(nil) }
+
+// UnmarshalText sets v to the D_Float nearest the decimal number in text, or
+// to the D_Float in hex form.
+func (v *VaxDFloat) UnmarshalText(text []byte) error {
+	if isHex(text) {
+		return unmarshalHex(text, v)
+	}
+	f, _, err := big.ParseFloat(string(text), 10, vaxDFloatPrec, big.ToNearestEven)
+	if err != nil {
+		return err
+	}
+	x, err := vaxDFloatfromBigFloat(f)
+	if err != nil {
+		return err
+	}
+	*v = x
+	return nil
+}
+
+// vaxDFloatPrec is the precision, in bits, of a D_Float mantissa including
+// the hidden bit.
+const vaxDFloatPrec = 56
+
+// bigFloat returns the exact value of the D_Float, which is not a reserved
+// operand, with a precision of vaxDFloatPrec.
+func (v VaxDFloat) bigFloat() *big.Float {
+	return vaxBigFloat(v.Sign(), v.Exponent(), v.Fraction(), vaxDFloatPrec, int(VaxDExponentBias))
+}
+
+// vaxDFloatfromBigFloat returns the D_Float nearest to x. Values outside the
+// D_Float range convert as their float64 does.
+func vaxDFloatfromBigFloat(x *big.Float) (VaxDFloat, error) {
+	sign, exp, frac, ok := vaxFieldsfromBigFloat(x, vaxDFloatPrec, int(VaxDExponentBias), int(VaxDExponentMask>>VaxDMantissaSize))
+	if !ok {
+		f, _ := x.Float64()
+		return VaxDFloatfromFloat64(f)
+	}
+	return VaxDFloatfromFields(sign, exp, frac)
+}
+
+// vaxBigFloat returns the exact value of the VAX fields with a mantissa of
+// prec bits, including the hidden bit, and the given exponent bias.
+func vaxBigFloat(sign uint, exp int, frac uint64, prec uint, bias int) *big.Float {
+	x := new(big.Float).SetPrec(prec)
+	if exp == 0 {
+		return x
+	}
+	x.SetUint64(1<<(prec-1) | frac)
+	x.SetMantExp(x, exp-bias-int(prec))
+	if sign != 0 {
+		x.Neg(x)
+	}
+	return x
+}
+
+// vaxFieldsfromBigFloat returns the VAX fields of x rounded to prec bits,
+// nearest, ties to even, with the given exponent bias. ok is false for zero
+// and for values whose exponent lies outside 1 to emax.
+func vaxFieldsfromBigFloat(x *big.Float, prec uint, bias, emax int) (sign uint, exp int, frac uint64, ok bool) {
+	if x.Sign() == 0 {
+		return 0, 0, 0, false
+	}
+
+	m := new(big.Float).SetMode(big.ToNearestEven).SetPrec(prec).Set(x)
+	if m.IsInf() {
+		return 0, 0, 0, false
+	}
+	if exp = m.MantExp(m) + bias; exp <= 0 || exp > emax {
+		return 0, 0, 0, false
+	}
+
+	frac, _ = m.Abs(m).SetMantExp(m, int(prec)).Uint64()
+	if x.Signbit() {
+		sign = 1
+	}
+	return sign, exp, frac &^ (1 << (prec - 1)), true
+}
+
+// MarshalJSON returns the D_Float as a JSON number.
+func (v VaxDFloat) MarshalJSON() ([]byte, error) { return marshalJSON(v) }
+
+// UnmarshalJSON sets v to the D_Float in a JSON number or string.
+func (v *VaxDFloat) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// AppendText appends the G_Float to b as a decimal number.
+func (v VaxGFloat) AppendText(b []byte) ([]byte, error) {
+	if v.IsReservedOperand() {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		return appendHex(b, buf[:]), nil
+	}
+	return v.bigFloat().Append(b, 'g', -1), nil
+}
+
+// MarshalText returns the G_Float as a decimal number.
+func (v VaxGFloat) MarshalText() ([]byte, error) { return v.AppendText(nil) }
+
+// UnmarshalText sets v to the G_Float nearest the decimal number in text, or
+// to the G_Float in hex form.
+func (v *VaxGFloat) UnmarshalText(text []byte) error {
+	if isHex(text) {
+		return unmarshalHex(text, v)
+	}
+	f, _, err := big.ParseFloat(string(text), 10, vaxGFloatPrec, big.ToNearestEven)
+	if err != nil {
+		return err
+	}
+	x, err := vaxGFloatfromBigFloat(f)
+	if err != nil {
+		return err
+	}
+	*v = x
+	return nil
+}
+
+// vaxGFloatPrec is the precision, in bits, of a G_Float mantissa including
+// the hidden bit.
+const vaxGFloatPrec = 53
+
+// bigFloat returns the exact value of the G_Float, which is not a reserved
+// operand, with a precision of vaxGFloatPrec.
+func (v VaxGFloat) bigFloat() *big.Float {
+	return vaxBigFloat(v.Sign(), v.Exponent(), v.Fraction(), vaxGFloatPrec, int(VaxGExponentBias))
+}
+
+// vaxGFloatfromBigFloat returns the G_Float nearest to x. Values outside the
+// G_Float range convert as their float64 does.
+func vaxGFloatfromBigFloat(x *big.Float) (VaxGFloat, error) {
+	sign, exp, frac, ok := vaxFieldsfromBigFloat(x, vaxGFloatPrec, int(VaxGExponentBias), int(VaxGExponentMask>>VaxGMantissaSize))
+	if !ok {
+		f, _ := x.Float64()
+		return VaxGFloatfromFloat64(f)
+	}
+	return VaxGFloatfromFields(sign, exp, frac)
+}
+
+// MarshalJSON returns the G_Float as a JSON number.
+func (v VaxGFloat) MarshalJSON() ([]byte, error) { return marshalJSON(v) }
+
+// UnmarshalJSON sets v to the G_Float in a JSON number or string.
+func (v *VaxGFloat) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// AppendText appends the H_Float to b as a decimal number.
+func (v VaxHFloat) AppendText(b []byte) ([]byte, error) {
+	x, err := BigFloatfromVaxHFloat(v[:])
+	if err != nil {
+		// Reserved operand
+		return appendHex(b, v[:]), nil
+	}
+	return x.Append(b, 'g', -1), nil
+}
+
+// MarshalText returns the H_Float as a decimal number.
+func (v VaxHFloat) MarshalText() ([]byte, error) { return v.AppendText(nil) }
+
+// UnmarshalText sets v to the H_Float nearest the decimal number in text, or
+// to the H_Float in hex form.
+func (v *VaxHFloat) UnmarshalText(text []byte) error {
+	if isHex(text) {
+		return unmarshalHex(text, v)
+	}
+	f, _, err := big.ParseFloat(string(text), 10, VaxHFloatPrec, big.ToNearestEven)
+	if err != nil {
+		return err
+	}
+	x, err := VaxHFloatfromBigFloat(f)
+	if err != nil {
+		return err
+	}
+	*v = x
+	return nil
+}
+
+// MarshalJSON returns the H_Float as a JSON number.
+func (v VaxHFloat) MarshalJSON() ([]byte, error) { return marshalJSON(v) }
+
+// UnmarshalJSON sets v to the H_Float in a JSON number or string.
+func (v *VaxHFloat) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// VaxFloat is the set of VAX floating point types.
+type VaxFloat interface {
+	VaxFFloat | VaxDFloat | VaxGFloat | VaxHFloat
+}
+
+// Hex holds a VAX floating point value that always marshals to text and JSON
+// in hex form, so that its bits are carried exactly:
+//
+//	type Sample struct {
+//	  Value vaxdata.Hex[vaxdata.VaxFFloat] `json:"value"` // "0x0FD04149"
+//	}
+//
+// Unmarshaling accepts either form, as for the wrapped type.
+type Hex[T VaxFloat] struct {
+	V T
+}
+
+// AppendText appends the value to b in hex form.
+func (h Hex[T]) AppendText(b []byte) ([]byte, error) {
+	raw, err := any(h.V).(encoding.BinaryAppender).AppendBinary(nil)
+	if err != nil {
+		return b, err
+	}
+	return appendHex(b, raw), nil
+}
+
+// MarshalText returns the value in hex form.
+func (h Hex[T]) MarshalText() ([]byte, error) { return h.AppendText(nil) }
+
+// UnmarshalText sets the value from text in hex form or as a decimal number.
+func (h *Hex[T]) UnmarshalText(text []byte) error {
+	return any(&h.V).(encoding.TextUnmarshaler).UnmarshalText(text)
+}
+
+// UnmarshalJSON sets the value from a JSON string or number.
+func (h *Hex[T]) UnmarshalJSON(b []byte) error {
+	return any(&h.V).(json.Unmarshaler).UnmarshalJSON(b)
+}
+
+// appendHex appends the hex form of the bytes in raw to b.
+func appendHex(b, raw []byte) []byte {
+	const digits = "0123456789ABCDEF"
+	b = append(b, "0x"...)
+	for _, c := range raw {
+		b = append(b, digits[c>>4], digits[c&0x0F])
+	}
+	return b
+}
+
+// isHex reports whether text is in hex form rather than a decimal number.
+func isHex(text []byte) bool {
+	return len(text) >= 2 && text[0] == '0' && (text[1] == 'x' || text[1] == 'X')
+}
+
+// unmarshalHex sets u from the bytes held by text in hex form.
+func unmarshalHex(text []byte, u encoding.BinaryUnmarshaler) error {
+	raw := make([]byte, hex.DecodedLen(len(text)-2))
+	if _, err := hex.Decode(raw, text[2:]); err != nil {
+		return err
+	}
+	return u.UnmarshalBinary(raw)
+}
+
+// marshalJSON returns the text form of m as a JSON number, or as a string
+// when it is in hex form.
+func marshalJSON(m encoding.TextAppender) ([]byte, error) {
+	b, err := m.AppendText([]byte{'"'})
+	if err != nil {
+		return nil, err
+	}
+	if isHex(b[1:]) {
+		return append(b, '"'), nil
+	}
+	return b[1:], nil
+}
+
+// unmarshalJSON sets u from the text in a JSON number or string. A JSON null
+// leaves u unchanged.
+func unmarshalJSON(b []byte, u encoding.TextUnmarshaler) error {
+	if string(b) == "null" {
+		return nil
+	}
+	if bytes.HasPrefix(b, []byte{'"'}) {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		b = []byte(s)
+	}
+	return u.UnmarshalText(b)
+}
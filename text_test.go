@@ -0,0 +1,141 @@
+package vaxdata
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestVaxFloatText(t *testing.T) {
+	var tests = []struct {
+		v    interface{ MarshalText() ([]byte, error) }
+		u    interface{ UnmarshalText([]byte) error }
+		text string
+	}{
+		{VaxFFloat(0x0FD04149), new(VaxFFloat), "3.14159"},
+		{VaxFFloat(0x0FD0C149), new(VaxFFloat), "-3.14159"},
+		{VaxFFloat(0x00000000), new(VaxFFloat), "0"},
+		{VaxFFloat(0x00008000), new(VaxFFloat), "0x00008000"},
+		{VaxDFloat(0x68C0A2210FDA4149), new(VaxDFloat), "3.1415926535897931"},
+		{VaxDFloat(0x68C7A2210FDA4149), new(VaxDFloat), "3.1415926535897935"},
+		{VaxGFloat(0x2D18544421FB4029), new(VaxGFloat), "3.141592653589793"},
+		{VaxGFloat(0x0000000000008000), new(VaxGFloat), "0x0000000000008000"},
+		{VaxHFloat{14: 0x40}, new(VaxHFloat), "0.5"},
+		{VaxHFloat{14: 0x80}, new(VaxHFloat), "0x00000000000000000000000000008000"},
+	}
+
+	for _, tt := range tests {
+		b, err := tt.v.MarshalText()
+		if err != nil || string(b) != tt.text {
+			t.Errorf("%T(%v).MarshalText() == %q, %v, want %q", tt.v, tt.v, b, err, tt.text)
+		}
+		if err := tt.u.UnmarshalText([]byte(tt.text)); err != nil {
+			t.Errorf("%T.UnmarshalText(%q) raised unexpected error: %q", tt.u, tt.text, err)
+		}
+		if got := reflectValue(tt.u); got != tt.v {
+			t.Errorf("%T.UnmarshalText(%q) == %v, want %v", tt.u, tt.text, got, tt.v)
+		}
+	}
+}
+
+func TestVaxFloatTextErrors(t *testing.T) {
+	var tests = []struct {
+		u    interface{ UnmarshalText([]byte) error }
+		text string
+	}{
+		{new(VaxFFloat), "pi"},
+		{new(VaxFFloat), "1e39"},
+		{new(VaxFFloat), "0x0FD041"},
+		{new(VaxDFloat), "NaN"},
+		{new(VaxDFloat), "1e39"},
+		{new(VaxGFloat), "0xZZ"},
+		{new(VaxHFloat), "Inf"},
+	}
+
+	for _, tt := range tests {
+		if err := tt.u.UnmarshalText([]byte(tt.text)); err == nil {
+			t.Errorf("%T.UnmarshalText(%q) did not raise an error", tt.u, tt.text)
+		}
+	}
+}
+
+// checkTextRoundTrip reports whether v survives text and JSON unchanged.
+func checkTextRoundTrip[T VaxFFloat | VaxDFloat | VaxGFloat, P interface {
+	*T
+	encoding.TextUnmarshaler
+}](t *testing.T, v T) {
+	t.Helper()
+
+	var got T
+	b, err := any(v).(encoding.TextMarshaler).MarshalText()
+	if err == nil {
+		err = P(&got).UnmarshalText(b)
+	}
+	if err != nil || got != v {
+		t.Errorf("%T(%X) text round trip via %q == %X, %v", v, v, b, got, err)
+	}
+
+	got = 0
+	b, err = json.Marshal(v)
+	if err == nil {
+		err = json.Unmarshal(b, &got)
+	}
+	if err != nil || got != v {
+		t.Errorf("%T(%X) JSON round trip via %s == %X, %v", v, v, b, got, err)
+	}
+}
+
+func TestVaxFloatTextRoundTrip(t *testing.T) {
+	// Every bit of the mantissa survives text and JSON, including those of
+	// F_Float and G_Float exponents 1 and 2, which are IEEE subnormals
+	r := rand.New(rand.NewPCG(5, 6))
+	for i := range 1000 {
+		sign := r.UintN(2)
+		fexp, gexp := 1+r.IntN(255), 1+r.IntN(2047)
+		if i%4 == 0 {
+			fexp, gexp = 1+r.IntN(2), 1+r.IntN(2)
+		}
+
+		f, _ := VaxFFloatfromFields(sign, fexp, r.Uint32N(VaxFMantissaMask+1))
+		checkTextRoundTrip(t, f)
+		d, _ := VaxDFloatfromFields(sign, 1+r.IntN(255), r.Uint64N(1<<55))
+		checkTextRoundTrip(t, d)
+		g, _ := VaxGFloatfromFields(sign, gexp, r.Uint64N(1<<52))
+		checkTextRoundTrip(t, g)
+	}
+
+	checkTextRoundTrip(t, VaxFFloat(0xFFFF00FF))
+	checkTextRoundTrip(t, VaxGFloat(0xFFFFFFFFFFFF001F))
+}
+
+func TestVaxFloatJSON(t *testing.T) {
+	type sample struct {
+		F   VaxFFloat
+		G   VaxGFloat
+		Bad VaxFFloat
+		Raw Hex[VaxFFloat]
+	}
+	in := sample{F: 0x0FD04149, G: 0x2D18544421FB4029, Bad: 0x00008000, Raw: Hex[VaxFFloat]{0x00000001}}
+	want := `{"F":3.14159,"G":3.141592653589793,"Bad":"0x00008000","Raw":"0x00000001"}`
+
+	b, err := json.Marshal(in)
+	if err != nil || string(b) != want {
+		t.Errorf("json.Marshal(%+v) == %s, %v, want %s", in, b, err, want)
+	}
+
+	var out sample
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("json.Unmarshal(%s) raised unexpected error: %q", b, err)
+	}
+	if out != in {
+		t.Errorf("json round trip == %+v, want %+v", out, in)
+	}
+
+	if err := json.Unmarshal([]byte(`{"F":"3.14159","Raw":3.14159,"G":null}`), &out); err != nil {
+		t.Fatalf("json.Unmarshal raised unexpected error: %q", err)
+	}
+	if out.F != 0x0FD04149 || out.Raw.V != 0x0FD04149 || out.G != in.G {
+		t.Errorf("json.Unmarshal == %+v", out)
+	}
+}
@@ -1,15 +1,18 @@
 package vaxdata
 
 import (
-	"errors"
+	"encoding/binary"
 	"io"
 	"math"
+	"math/big"
 )
 
 // VaxFFloatReader reads float32 values from F_Float's in the underlying io.Reader.
 type VaxFFloatReader struct {
 	r   io.Reader
 	buf []byte
+	n   int64
+	c   Converter
 }
 
 // NewVaxFFloatReader creates a new VaxFFloatReader. VaxFFloatReader.Read reads
@@ -22,15 +25,42 @@ func NewVaxFFloatReader(r io.Reader) *VaxFFloatReader {
 }
 
 // Read takes a F_Float from the underlying io.Reader and returns a float32.
+// Values that cannot be converted are passed to the ErrorHandler set by
+// OnReadError, if any.
 func (vaxin *VaxFFloatReader) Read() (float32, error) {
-	if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
-		return 0, err
+	for {
+		if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+			return 0, err
+		}
+		vaxin.n++
+
+		f, err := vaxin.c.Float32fromVaxFFloat(vaxin.buf)
+		if err == nil || vaxin.c.onError == nil {
+			return f, err
+		}
+		switch d, v := vaxin.c.readFault(vaxin.n-1, 4, uint64(binary.BigEndian.Uint32(vaxin.buf)), err); d {
+		case Substitute:
+			return float32(v), nil
+		case Skip:
+			continue
+		}
+		return f, err
 	}
-	return Float32fromVaxFFloat(vaxin.buf)
+}
+
+// Count returns the number of values read, including any skipped.
+func (vaxin *VaxFFloatReader) Count() int64 {
+	return vaxin.n
 }
 
 // Float32fromVaxFFloat returns the float32 representation of a VAX F_Float.
+// Values that need IEEE subnormal form are chopped; see Rounding.
 func Float32fromVaxFFloat(buf []byte) (float32, error) {
+	return float32fromVaxFFloat(buf, big.ToZero)
+}
+
+// float32fromVaxFFloat is Float32fromVaxFFloat, rounding subnormals by mode.
+func float32fromVaxFFloat(buf []byte, mode big.RoundingMode) (float32, error) {
 	const (
 		MantissaMask                     = VaxFMantissaMask
 		MantissaSize                     = VaxFMantissaSize
@@ -51,7 +81,7 @@ func Float32fromVaxFFloat(buf []byte) (float32, error) {
 		if (vaxpart1 & SignBit) == SignBit {
 			// If negative [s=1]
 			// fixup to IEEE zero
-			return 0, errors.New("F_Float to S_Float: VAX reserved operand fault")
+			return 0, &ConversionError{"F_Float", false, VaxFFloat(binary.BigEndian.Uint32(buf)), float32(0), ErrReservedOperand}
 		}
 
 		// Set VAX dirty [m<>0] or true [m=0] zero to IEEE +zero [s=e=m=0]
@@ -80,8 +110,10 @@ func Float32fromVaxFFloat(buf []byte) (float32, error) {
 			// e + n = 1, thus n = 1 - e.  n is guaranteed to be at least 1
 			// [e<=0], which guarantees that the hidden 1.m bit from the ori-
 			// ginal mantissa will become visible, and the resulting subnor-
-			// mal mantissa will correctly be of the form 0.m.
-			result = (vaxpart1 & SignBit) | uint32((HiddenBit|(vaxpart1&MantissaMask))>>uint32(1-e))
+			// mal mantissa will correctly be of the form 0.m.  Rounding up
+			// may carry into the exponent field, giving the smallest normal.
+			m := roundShift(uint64(HiddenBit|(vaxpart1&MantissaMask)), uint(1-e), vaxpart1&SignBit != 0, mode)
+			result = (vaxpart1 & SignBit) | uint32(m)
 		}
 	}
 
@@ -1,15 +1,18 @@
 package vaxdata
 
 import (
-	"errors"
+	"encoding/binary"
 	"io"
 	"math"
+	"math/big"
 )
 
 // VaxGFloatReader reads float64 values from G_Float's in the underlying io.Reader.
 type VaxGFloatReader struct {
 	r   io.Reader
 	buf []byte
+	n   int64
+	c   Converter
 }
 
 // NewVaxGFloatReader creates a new VaxGFloatReader. VaxGFloatReader.Read reads
@@ -21,16 +24,43 @@ func NewVaxGFloatReader(r io.Reader) *VaxGFloatReader {
 	return vaxin
 }
 
-// Read takes a G_Float from the underlying io.Reader and returns a float64
+// Read takes a G_Float from the underlying io.Reader and returns a float64.
+// Values that cannot be converted are passed to the ErrorHandler set by
+// OnReadError, if any.
 func (vaxin *VaxGFloatReader) Read() (float64, error) {
-	if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
-		return 0, err
+	for {
+		if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+			return 0, err
+		}
+		vaxin.n++
+
+		f, err := vaxin.c.Float64fromVaxGFloat(vaxin.buf)
+		if err == nil || vaxin.c.onError == nil {
+			return f, err
+		}
+		switch d, v := vaxin.c.readFault(vaxin.n-1, 8, binary.BigEndian.Uint64(vaxin.buf), err); d {
+		case Substitute:
+			return v, nil
+		case Skip:
+			continue
+		}
+		return f, err
 	}
-	return Float64fromVaxGFloat(vaxin.buf)
+}
+
+// Count returns the number of values read, including any skipped.
+func (vaxin *VaxGFloatReader) Count() int64 {
+	return vaxin.n
 }
 
 // Float64fromVaxGFloat returns the float64 representation of a VAX G_Float.
+// Values that need IEEE subnormal form are chopped; see Rounding.
 func Float64fromVaxGFloat(buf []byte) (float64, error) {
+	return float64fromVaxGFloat(buf, big.ToZero)
+}
+
+// float64fromVaxGFloat is Float64fromVaxGFloat, rounding subnormals by mode.
+func float64fromVaxGFloat(buf []byte, mode big.RoundingMode) (float64, error) {
 	const (
 		MantissaMask              uint32 = VaxGMantissaMask
 		MantissaSize              uint32 = VaxGMantissaSize
@@ -53,7 +83,7 @@ func Float64fromVaxGFloat(buf []byte) (float64, error) {
 		if (vaxpart1 & SignBit) == SignBit {
 			// If negative [s=1]
 			// fixup to IEEE zero
-			err = errors.New("G_Float to T_Float: VAX reserved operand fault")
+			err = ErrReservedOperand
 		}
 
 		// Set VAX dirty [m<>0] or true [m=0] zero to IEEE +zero [s=e=m=0]
@@ -88,16 +118,115 @@ func Float64fromVaxGFloat(buf []byte) (float64, error) {
 			// e + n = 1, thus n = 1 - e.  n is guaranteed to be at least 1
 			// [e<=0], which guarantees that the hidden 1.m bit from the ori-
 			// ginal mantissa will become visible, and the resulting subnor-
-			// mal mantissa will correctly be of the form 0.m.
-			//
+			// mal mantissa will correctly be of the form 0.m.  Rounding up
+			// may carry into the exponent field, giving the smallest normal.
 
-			vaxpart1 = (vaxpart1 & (SignBit | MantissaMask)) | HiddenBit
-			ieeepart1 = (vaxpart1 & SignBit) | ((vaxpart1 & (HiddenBit | MantissaMask)) >> uint32(1-e))
-			ieeepart2 = (vaxpart1 << uint32(31+e)) | (vaxpart2 >> uint32(1-e))
+			m := (uint64(HiddenBit|(vaxpart1&MantissaMask)) << 32) | uint64(vaxpart2)
+			m = roundShift(m, uint(1-e), vaxpart1&SignBit != 0, mode)
+			ieeepart1 = (vaxpart1 & SignBit) | uint32(m>>32)
+			ieeepart2 = uint32(m)
 
 		}
 	}
 
-	result := uint64(uint64(ieeepart1)<<32) | uint64(ieeepart2)
-	return math.Float64frombits(result), err
+	result := math.Float64frombits(uint64(uint64(ieeepart1)<<32) | uint64(ieeepart2))
+	if err != nil {
+		return result, &ConversionError{"G_Float", false, VaxGFloat(binary.BigEndian.Uint64(buf)), result, err}
+	}
+	return result, nil
+}
+
+// VaxDFloatReader reads float64 values from D_Float's in the underlying io.Reader.
+type VaxDFloatReader struct {
+	r   io.Reader
+	buf []byte
+	n   int64
+	c   Converter
+}
+
+// NewVaxDFloatReader creates a new VaxDFloatReader. VaxDFloatReader.Read reads
+// a float64 from a D_Float in the underlying io.Reader.
+func NewVaxDFloatReader(r io.Reader) *VaxDFloatReader {
+	vaxin := new(VaxDFloatReader)
+	(*vaxin).r = r
+	(*vaxin).buf = make([]byte, 8)
+	return vaxin
+}
+
+// Read takes a D_Float from the underlying io.Reader and returns a float64.
+// Values that cannot be converted are passed to the ErrorHandler set by
+// OnReadError, if any.
+func (vaxin *VaxDFloatReader) Read() (float64, error) {
+	for {
+		if _, err := io.ReadFull(vaxin.r, vaxin.buf); err != nil {
+			return 0, err
+		}
+		vaxin.n++
+
+		f, err := vaxin.c.Float64fromVaxDFloat(vaxin.buf)
+		if err == nil || vaxin.c.onError == nil {
+			return f, err
+		}
+		switch d, v := vaxin.c.readFault(vaxin.n-1, 8, binary.BigEndian.Uint64(vaxin.buf), err); d {
+		case Substitute:
+			return v, nil
+		case Skip:
+			continue
+		}
+		return f, err
+	}
+}
+
+// Count returns the number of values read, including any skipped.
+func (vaxin *VaxDFloatReader) Count() int64 {
+	return vaxin.n
+}
+
+// Float64fromVaxDFloat returns the float64 representation of a VAX D_Float.
+//
+// A D_Float carries three more fraction bits than a float64, so unlike the
+// other conversions the result is rounded to nearest (ties to even) instead
+// of chopped; see Rounding. The D_Float exponent range fits within
+// T_Float's normalized range, so no subnormal form is needed.
+func Float64fromVaxDFloat(buf []byte) (float64, error) {
+	return float64fromVaxDFloat(buf, big.ToNearestEven)
+}
+
+// float64fromVaxDFloat is Float64fromVaxDFloat, rounding by mode.
+func float64fromVaxDFloat(buf []byte, mode big.RoundingMode) (float64, error) {
+	const (
+		MantissaMask       uint32 = VaxDMantissaMask
+		MantissaSize       uint32 = VaxDMantissaSize
+		ExponentAdjustment int32  = int32(1+VaxDExponentBias) - int32(IeeeTExponentBias)
+		ExcessBits         uint32 = (VaxDMantissaSize + 32) - (IeeeTMantissaSize + 32)
+	)
+
+	vaxpart2 := uint32FromVaxbits(buf[:4])
+	vaxpart1 := uint32FromVaxbits(buf[4:8])
+
+	e := int32(vaxpart1 & VaxDExponentMask)
+	if e == 0 {
+		// If the biased VAX exponent is zero [e=0]
+
+		if (vaxpart1 & SignBit) == SignBit {
+			// If negative [s=1]
+			// fixup to IEEE zero
+			return 0, &ConversionError{"D_Float", false, VaxDFloat(binary.BigEndian.Uint64(buf)), float64(0), ErrReservedOperand}
+		}
+
+		// Set VAX dirty [m<>0] or true [m=0] zero to IEEE +zero [s=e=m=0]
+		return 0, nil
+	}
+
+	e >>= MantissaSize      // Obtain the biased VAX exponent
+	e -= ExponentAdjustment // Always a normalized T_Float exponent [e>0]
+
+	// Assemble the 55-bit VAX mantissa and round away the excess bits
+	// Rounding may carry out of the mantissa, which correctly increments
+	// the exponent field below
+	m := (uint64(vaxpart1&MantissaMask) << 32) | uint64(vaxpart2)
+	m = roundShift(m, uint(ExcessBits), vaxpart1&SignBit != 0, mode)
+
+	result := (uint64(vaxpart1&SignBit) << 32) + (uint64(e) << (IeeeTMantissaSize + 32)) + m
+	return math.Float64frombits(result), nil
 }